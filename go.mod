@@ -9,6 +9,7 @@ require (
 	github.com/google/wire v0.6.0
 	github.com/hashicorp/go-hclog v1.6.3
 	github.com/hashicorp/go-multierror v1.1.1
+	github.com/klauspost/compress v1.18.0
 	github.com/raito-io/bexpression v0.1.2
 	github.com/raito-io/cli v0.70.0
 	github.com/raito-io/enumer v0.1.6
@@ -16,6 +17,7 @@ require (
 	github.com/raito-io/sdk-go v0.0.14
 	github.com/stretchr/testify v1.10.0
 	github.com/vektra/mockery/v2 v2.53.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -88,5 +90,4 @@ require (
 	google.golang.org/grpc v1.71.0 // indirect
 	google.golang.org/protobuf v1.36.5 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -1,7 +1,39 @@
 package constants
 
 const (
-	ManifestParameterName       = "manifest"
-	FullNamePrefixParameterName = "do-prefix"
-	TagSplitKey                 = "tag-split-key"
+	ManifestParameterName            = "manifest"
+	ManifestTokenParameterName       = "manifest-token"
+	ManifestTimeoutParameterName     = "manifest-timeout"
+	ManifestChecksumParameterName    = "manifest-checksum"
+	ManifestMaxSizeParameterName     = "manifest-max-size"
+	CatalogParameterName             = "catalog"
+	CatalogTokenParameterName        = "catalog-token"
+	DbtCloudAccountIdParameterName   = "dbt-cloud-account-id"
+	DbtCloudJobIdParameterName       = "dbt-cloud-job-id"
+	DbtCloudRunIdParameterName       = "dbt-cloud-run-id"
+	DbtCloudTokenParameterName       = "dbt-cloud-token"
+	DbtCloudUrlParameterName         = "dbt-cloud-url"
+	SelectParameterName              = "select"
+	ExcludeParameterName             = "exclude"
+	SelectorParameterName            = "selector"
+	FullNamePrefixParameterName      = "do-prefix"
+	IdentifierCasingParameterName    = "identifier-casing"
+	FullnameTemplateParameterName    = "fullname-template"
+	DatabaseMappingParameterName     = "database-mapping"
+	SchemaMappingParameterName       = "schema-mapping"
+	ModelVersionsParameterName       = "model-versions"
+	PolicyRuleVariablesParameterName = "policy-rule-variables"
+	DbtTargetParameterName           = "dbt-target"
+	TemplatesFileParameterName       = "templates-file"
+	CrossTableMasksParameterName     = "cross-table-masks"
+	MaskTypeMappingParameterName     = "mask-type-mapping"
+	RulesFileParameterName           = "rules-file"
+	PoliciesFileParameterName        = "policies-file"
+	TagSplitKey                      = "tag-split-key"
+	PropagateTagKeysParameterName    = "propagate-tag-keys"
+)
+
+const (
+	ModelVersionsAll    = "all"
+	ModelVersionsLatest = "latest"
 )
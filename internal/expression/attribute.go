@@ -0,0 +1,95 @@
+package expression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/raito-io/bexpression/base"
+	"github.com/raito-io/bexpression/datacomparison"
+)
+
+type AttributeRule = base.BinaryExpression[*AttributeComparison]
+
+// AttributeComparison compares an attribute of a user, e.g. the department, with a literal value.
+type AttributeComparison struct {
+	Attribute string                            `json:"attribute" yaml:"attribute"`
+	Operator  datacomparison.ComparisonOperator `json:"operator" yaml:"operator"`
+	Value     datacomparison.Literal            `json:"value" yaml:"value"`
+}
+
+func (c *AttributeComparison) Validate(ctx context.Context) error {
+	if c.Attribute == "" {
+		return errors.New("attribute is empty")
+	}
+
+	if !c.Operator.IsAComparisonOperator() {
+		return fmt.Errorf("invalid operator for attribute %q", c.Attribute)
+	}
+
+	err := c.Value.Validate(base.CtxExtendPathAndSetElement(ctx, "value", c))
+	if err != nil {
+		return fmt.Errorf("value of attribute %q: %w", c.Attribute, err)
+	}
+
+	return nil
+}
+
+func (c *AttributeComparison) Accept(ctx context.Context, visitor base.Visitor) error {
+	err := visitor.EnterExpressionElement(ctx, c)
+	if err != nil {
+		return fmt.Errorf("enter attribute comparison: %w", err)
+	}
+
+	defer visitor.LeaveExpressionElement(ctx, c)
+
+	err = visitor.Literal(base.CtxExtendPathAndSetElement(ctx, "attribute", c), c.Attribute)
+	if err != nil {
+		return fmt.Errorf("attribute: %w", err)
+	}
+
+	err = visitor.Literal(base.CtxExtendPathAndSetElement(ctx, "operator", c), c.Operator)
+	if err != nil {
+		return fmt.Errorf("operator: %w", err)
+	}
+
+	err = c.Value.Accept(base.CtxExtendPathAndSetElement(ctx, "value", c), visitor)
+	if err != nil {
+		return fmt.Errorf("value: %w", err)
+	}
+
+	return nil
+}
+
+func (c *AttributeComparison) ToGql() (base.BinaryExpressionUnion, error) {
+	return c, nil
+}
+
+func (c *AttributeComparison) IsBinaryExpression() {}
+
+// ParseAttributeRule parses a boolean expression over user attributes, e.g. `department = 'Finance' and clearance >= 2`.
+// Unquoted values are interpreted as booleans or numbers if possible.
+func ParseAttributeRule(ctx context.Context, input string) (*AttributeRule, error) {
+	rule, err := parse(input, func(c *Comparison) (*AttributeComparison, error) {
+		if c.Operator == "" {
+			return nil, fmt.Errorf("attribute %q should be compared with a value", c.Left.Value)
+		}
+
+		operator, err := comparisonOperator(c.Operator)
+		if err != nil {
+			return nil, err
+		}
+
+		return &AttributeComparison{Attribute: c.Left.Value, Operator: operator, Value: *literal(c.Right)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = rule.Validate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpression, err)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,75 @@
+package expression
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raito-io/bexpression/base"
+	"github.com/raito-io/bexpression/datacomparison"
+	"github.com/raito-io/bexpression/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAttributeRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *AttributeRule
+		wantErr string
+	}{
+		{
+			name:  "single comparison",
+			input: "department = Finance",
+			want: &AttributeRule{Comparison: &AttributeComparison{
+				Attribute: "department",
+				Operator:  datacomparison.ComparisonOperatorEqual,
+				Value:     datacomparison.Literal{Str: utils.Ptr("Finance")},
+			}},
+		},
+		{
+			name:  "typed values",
+			input: `department = 'Finance' and clearance >= 2 and score < 0.5 and contractor != false and level <> "3"`,
+			want: &AttributeRule{Aggregator: &base.Aggregator[*AttributeComparison]{
+				Operator: base.AggregatorOperatorAnd,
+				Operands: []base.BinaryExpression[*AttributeComparison]{
+					{Comparison: &AttributeComparison{Attribute: "department", Operator: datacomparison.ComparisonOperatorEqual, Value: datacomparison.Literal{Str: utils.Ptr("Finance")}}},
+					{Comparison: &AttributeComparison{Attribute: "clearance", Operator: datacomparison.ComparisonOperatorGreaterThanOrEqual, Value: datacomparison.Literal{Int: utils.Ptr(2)}}},
+					{Comparison: &AttributeComparison{Attribute: "score", Operator: datacomparison.ComparisonOperatorLessThan, Value: datacomparison.Literal{Float: utils.Ptr(0.5)}}},
+					{Comparison: &AttributeComparison{Attribute: "contractor", Operator: datacomparison.ComparisonOperatorNotEqual, Value: datacomparison.Literal{Bool: utils.Ptr(false)}}},
+					{Comparison: &AttributeComparison{Attribute: "level", Operator: datacomparison.ComparisonOperatorNotEqual, Value: datacomparison.Literal{Str: utils.Ptr("3")}}},
+				},
+			}},
+		},
+		{
+			name:    "missing value",
+			input:   "department and clearance > 2",
+			wantErr: `invalid expression: position 0: attribute "department" should be compared with a value`,
+		},
+		{
+			name:    "unsupported operator",
+			input:   "department : Finance",
+			wantErr: `invalid expression: position 0: unsupported comparison operator ":"`,
+		},
+		{
+			name:    "unknown operator",
+			input:   "clearance => 2",
+			wantErr: `invalid expression: unknown operator "=>" at position 10`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAttributeRule(context.Background(), tt.input)
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
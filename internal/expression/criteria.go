@@ -0,0 +1,73 @@
+package expression
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/raito-io/bexpression"
+	"github.com/raito-io/bexpression/datacomparison"
+)
+
+// ParseCriteria parses row filter criteria, e.g. `region = 'EU' and (amount > 100 or priority = true)`.
+// Unquoted and double-quoted identifiers are column references, single-quoted values are strings and unquoted booleans and numbers are literals.
+// The name of each referenced column is converted by columnName, which is called with quoted set for double-quoted identifiers.
+func ParseCriteria(ctx context.Context, input string, columnName func(name string, quoted bool) string) (*bexpression.DataComparisonExpression, error) {
+	criteria, err := parse(input, func(c *Comparison) (*datacomparison.DataComparison, error) {
+		if c.Operator == "" {
+			return nil, fmt.Errorf("%q should be compared with a value or column", c.Left.Value)
+		}
+
+		operator, err := comparisonOperator(c.Operator)
+		if err != nil {
+			return nil, err
+		}
+
+		return &datacomparison.DataComparison{
+			Operator:     operator,
+			LeftOperand:  criteriaOperand(c.Left, columnName),
+			RightOperand: criteriaOperand(c.Right, columnName),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = criteria.Validate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpression, err)
+	}
+
+	return criteria, nil
+}
+
+func criteriaOperand(operand Operand, columnName func(name string, quoted bool) string) datacomparison.Operand {
+	value := literal(operand)
+	if operand.DoubleQuoted || (value.Str != nil && !operand.Quoted) {
+		return datacomparison.Operand{Reference: &datacomparison.Reference{
+			EntityType: datacomparison.EntityTypeColumnReferenceByName,
+			EntityID:   columnName(operand.Value, operand.DoubleQuoted),
+		}}
+	}
+
+	return datacomparison.Operand{Literal: value}
+}
+
+// ReferencedColumns returns the names of the columns referenced in the criteria, in order of appearance.
+func ReferencedColumns(ctx context.Context, criteria *bexpression.DataComparisonExpression) ([]string, error) {
+	var columns []string
+
+	visitor := bexpression.NewFunctionVisitor(bexpression.WithLiteralFn(func(_ context.Context, l interface{}) error {
+		if reference, ok := l.(*datacomparison.Reference); ok && reference.EntityType == datacomparison.EntityTypeColumnReferenceByName {
+			columns = append(columns, reference.EntityID)
+		}
+
+		return nil
+	}))
+
+	err := criteria.Accept(ctx, visitor)
+	if err != nil {
+		return nil, fmt.Errorf("visit criteria: %w", err)
+	}
+
+	return columns, nil
+}
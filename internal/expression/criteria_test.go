@@ -0,0 +1,121 @@
+package expression
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/raito-io/bexpression"
+	"github.com/raito-io/bexpression/base"
+	"github.com/raito-io/bexpression/datacomparison"
+	"github.com/raito-io/bexpression/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCriteria(t *testing.T) {
+	column := func(name string) datacomparison.Operand {
+		return datacomparison.Operand{Reference: &datacomparison.Reference{EntityType: datacomparison.EntityTypeColumnReferenceByName, EntityID: name}}
+	}
+
+	upper := func(name string, quoted bool) string {
+		if quoted {
+			return name
+		}
+
+		return strings.ToUpper(name)
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		columnName  func(name string, quoted bool) string
+		want        *bexpression.DataComparisonExpression
+		wantColumns []string
+		wantErr     string
+	}{
+		{
+			name:  "column and literal",
+			input: "region = 'EU'",
+			want: &bexpression.DataComparisonExpression{Comparison: &datacomparison.DataComparison{
+				Operator:     datacomparison.ComparisonOperatorEqual,
+				LeftOperand:  column("region"),
+				RightOperand: datacomparison.Operand{Literal: &datacomparison.Literal{Str: utils.Ptr("EU")}},
+			}},
+			wantColumns: []string{"region"},
+		},
+		{
+			name:  "columns on both sides and numbers",
+			input: "not (shipped_at < ordered_at) or amount >= 100.5",
+			want: &bexpression.DataComparisonExpression{Aggregator: &bexpression.DataComparisonAggregator{
+				Operator: base.AggregatorOperatorOr,
+				Operands: []bexpression.DataComparisonExpression{
+					{UnaryExpression: &bexpression.DataComparisonUnaryExpression{
+						Operator: base.UnaryOperatorNot,
+						Operand: bexpression.DataComparisonExpression{Comparison: &datacomparison.DataComparison{
+							Operator:     datacomparison.ComparisonOperatorLessThan,
+							LeftOperand:  column("shipped_at"),
+							RightOperand: column("ordered_at"),
+						}},
+					}},
+					{Comparison: &datacomparison.DataComparison{
+						Operator:     datacomparison.ComparisonOperatorGreaterThanOrEqual,
+						LeftOperand:  column("amount"),
+						RightOperand: datacomparison.Operand{Literal: &datacomparison.Literal{Float: utils.Ptr(100.5)}},
+					}},
+				},
+			}},
+			wantColumns: []string{"shipped_at", "ordered_at", "amount"},
+		},
+		{
+			name:       "double-quoted identifiers are columns",
+			input:      `region = "Region" and "amount" > '100'`,
+			columnName: upper,
+			want: &bexpression.DataComparisonExpression{Aggregator: &bexpression.DataComparisonAggregator{
+				Operator: base.AggregatorOperatorAnd,
+				Operands: []bexpression.DataComparisonExpression{
+					{Comparison: &datacomparison.DataComparison{
+						Operator:     datacomparison.ComparisonOperatorEqual,
+						LeftOperand:  column("REGION"),
+						RightOperand: column("Region"),
+					}},
+					{Comparison: &datacomparison.DataComparison{
+						Operator:     datacomparison.ComparisonOperatorGreaterThan,
+						LeftOperand:  column("amount"),
+						RightOperand: datacomparison.Operand{Literal: &datacomparison.Literal{Str: utils.Ptr("100")}},
+					}},
+				},
+			}},
+			wantColumns: []string{"REGION", "Region", "amount"},
+		},
+		{
+			name:    "column without comparison",
+			input:   "is_active",
+			wantErr: `invalid expression: position 0: "is_active" should be compared with a value or column`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columnName := tt.columnName
+			if columnName == nil {
+				columnName = func(name string, _ bool) string { return name }
+			}
+
+			got, err := ParseCriteria(context.Background(), tt.input, columnName)
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			columns, err := ReferencedColumns(context.Background(), got)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantColumns, columns)
+		})
+	}
+}
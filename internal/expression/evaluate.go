@@ -0,0 +1,27 @@
+package expression
+
+import (
+	"github.com/raito-io/bexpression/base"
+)
+
+// evaluate evaluates a validated expression. Each comparison is evaluated by the match function.
+func evaluate[T base.Comparison](expression *base.BinaryExpression[T], match func(c T) bool) bool {
+	switch {
+	case expression.Literal != nil:
+		return *expression.Literal
+	case expression.Aggregator != nil:
+		and := expression.Aggregator.Operator == base.AggregatorOperatorAnd
+
+		for i := range expression.Aggregator.Operands {
+			if evaluate(&expression.Aggregator.Operands[i], match) != and {
+				return !and
+			}
+		}
+
+		return and
+	case expression.UnaryExpression != nil:
+		return !evaluate(&expression.UnaryExpression.Operand, match)
+	default:
+		return match(expression.Comparison)
+	}
+}
@@ -0,0 +1,330 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/raito-io/bexpression/base"
+	"github.com/raito-io/bexpression/datacomparison"
+	"github.com/raito-io/bexpression/utils"
+)
+
+var ErrInvalidExpression = errors.New("invalid expression")
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenOpenParenthesis
+	tokenCloseParenthesis
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+	quote rune
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("%q", t.value)
+	case tokenWord, tokenOperator, tokenOpenParenthesis, tokenCloseParenthesis:
+		return fmt.Sprintf("'%s'", t.value)
+	default:
+		return t.value
+	}
+}
+
+func (t token) operand() Operand {
+	return Operand{Value: t.value, Quoted: t.kind == tokenString, DoubleQuoted: t.kind == tokenString && t.quote == '"'}
+}
+
+// isKeyword returns true if the token is the (case-insensitive) keyword. Quoted strings are never keywords.
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.value, keyword)
+}
+
+// Comparison is a single comparison of an expression, e.g. `region = 'EU'`.
+// If the expression only contains an operand, e.g. `pii`, Operator is empty and Right is not set.
+type Comparison struct {
+	Left     Operand
+	Operator string
+	Right    Operand
+}
+
+// Operand is a word or a quoted string of an expression.
+// DoubleQuoted is set if the operand was quoted with `"` instead of `'`.
+type Operand struct {
+	Value        string
+	Quoted       bool
+	DoubleQuoted bool
+}
+
+// parse parses a boolean expression with `and`, `or`, `not` and parentheses. Each comparison is converted by the comparison function.
+// `and` takes precedence over `or`, e.g. `a or b and c` is parsed as `a or (b and c)`.
+func parse[T base.Comparison](input string, comparison func(c *Comparison) (T, error)) (*base.BinaryExpression[T], error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser[T]{tokens: tokens, comparison: comparison}
+
+	result, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %s at position %d", ErrInvalidExpression, next, next.pos)
+	}
+
+	return result, nil
+}
+
+type parser[T base.Comparison] struct {
+	tokens     []token
+	idx        int
+	comparison func(c *Comparison) (T, error)
+}
+
+func (p *parser[T]) peek() token {
+	return p.tokens[p.idx]
+}
+
+func (p *parser[T]) next() token {
+	t := p.tokens[p.idx]
+
+	if t.kind != tokenEOF {
+		p.idx++
+	}
+
+	return t
+}
+
+func (p *parser[T]) or() (*base.BinaryExpression[T], error) {
+	return p.aggregate("or", base.AggregatorOperatorOr, p.and)
+}
+
+func (p *parser[T]) and() (*base.BinaryExpression[T], error) {
+	return p.aggregate("and", base.AggregatorOperatorAnd, p.unary)
+}
+
+func (p *parser[T]) aggregate(keyword string, operator base.AggregatorOperator, operand func() (*base.BinaryExpression[T], error)) (*base.BinaryExpression[T], error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []base.BinaryExpression[T]{*first}
+
+	for p.peek().isKeyword(keyword) {
+		p.next()
+
+		other, operandErr := operand()
+		if operandErr != nil {
+			return nil, operandErr
+		}
+
+		operands = append(operands, *other)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	return &base.BinaryExpression[T]{
+		Aggregator: &base.Aggregator[T]{
+			Operator: operator,
+			Operands: operands,
+		},
+	}, nil
+}
+
+func (p *parser[T]) unary() (*base.BinaryExpression[T], error) {
+	if !p.peek().isKeyword("not") {
+		return p.primary()
+	}
+
+	p.next()
+
+	operand, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &base.BinaryExpression[T]{
+		UnaryExpression: &base.UnaryExpression[T]{
+			Operator: base.UnaryOperatorNot,
+			Operand:  *operand,
+		},
+	}, nil
+}
+
+func (p *parser[T]) primary() (*base.BinaryExpression[T], error) {
+	t := p.next()
+
+	switch {
+	case t.kind == tokenOpenParenthesis:
+		result, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenCloseParenthesis {
+			return nil, fmt.Errorf("%w: expected ')' at position %d, got %s", ErrInvalidExpression, closing.pos, closing)
+		}
+
+		return result, nil
+	case t.isKeyword("true"), t.isKeyword("false"):
+		literal := t.isKeyword("true")
+
+		return &base.BinaryExpression[T]{Literal: &literal}, nil
+	case t.kind == tokenWord || t.kind == tokenString:
+		c := Comparison{Left: t.operand()}
+
+		if p.peek().kind == tokenOperator {
+			c.Operator = p.next().value
+
+			right := p.next()
+			if right.kind != tokenWord && right.kind != tokenString {
+				return nil, fmt.Errorf("%w: expected value after %q at position %d, got %s", ErrInvalidExpression, c.Operator, right.pos, right)
+			}
+
+			c.Right = right.operand()
+		}
+
+		comparison, err := p.comparison(&c)
+		if err != nil {
+			return nil, fmt.Errorf("%w: position %d: %w", ErrInvalidExpression, t.pos, err)
+		}
+
+		return &base.BinaryExpression[T]{Comparison: comparison}, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected %s at position %d", ErrInvalidExpression, t, t.pos)
+	}
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpenParenthesis, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenCloseParenthesis, value: ")", pos: i})
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+
+			if end == len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string at position %d", ErrInvalidExpression, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: string(runes[i+1 : end]), pos: i, quote: r})
+			i = end + 1
+		case isOperatorRune(r):
+			end := i + 1
+			for end < len(runes) && isOperatorRune(runes[end]) {
+				end++
+			}
+
+			operator := string(runes[i:end])
+			if !isOperator(operator) {
+				return nil, fmt.Errorf("%w: unknown operator %q at position %d", ErrInvalidExpression, operator, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, value: operator, pos: i})
+			i = end
+		default:
+			end := i + 1
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[i:end]), pos: i})
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func isOperatorRune(r rune) bool {
+	return strings.ContainsRune("=!<>:", r)
+}
+
+func isOperator(operator string) bool {
+	switch operator {
+	case "=", "==", "!=", "<>", "<", "<=", ">", ">=", ":":
+		return true
+	default:
+		return false
+	}
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !isOperatorRune(r) && !strings.ContainsRune("()'\"", r)
+}
+
+// comparisonOperator returns the data comparison operator of an operator of an expression.
+func comparisonOperator(operator string) (datacomparison.ComparisonOperator, error) {
+	switch operator {
+	case "=", "==":
+		return datacomparison.ComparisonOperatorEqual, nil
+	case "!=", "<>":
+		return datacomparison.ComparisonOperatorNotEqual, nil
+	case "<":
+		return datacomparison.ComparisonOperatorLessThan, nil
+	case "<=":
+		return datacomparison.ComparisonOperatorLessThanOrEqual, nil
+	case ">":
+		return datacomparison.ComparisonOperatorGreaterThan, nil
+	case ">=":
+		return datacomparison.ComparisonOperatorGreaterThanOrEqual, nil
+	default:
+		return 0, fmt.Errorf("unsupported comparison operator %q", operator)
+	}
+}
+
+// literal converts an operand into a literal. Quoted operands are always strings, unquoted operands are booleans or numbers if possible.
+func literal(operand Operand) *datacomparison.Literal {
+	if operand.Quoted {
+		return &datacomparison.Literal{Str: utils.Ptr(operand.Value)}
+	}
+
+	if strings.EqualFold(operand.Value, "true") || strings.EqualFold(operand.Value, "false") {
+		return &datacomparison.Literal{Bool: utils.Ptr(strings.EqualFold(operand.Value, "true"))}
+	}
+
+	if i, err := strconv.Atoi(operand.Value); err == nil {
+		return &datacomparison.Literal{Int: &i}
+	}
+
+	if f, err := strconv.ParseFloat(operand.Value, 64); err == nil {
+		return &datacomparison.Literal{Float: &f}
+	}
+
+	return &datacomparison.Literal{Str: utils.Ptr(operand.Value)}
+}
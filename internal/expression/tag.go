@@ -0,0 +1,91 @@
+package expression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/raito-io/bexpression/base"
+)
+
+// DefaultTagKey is the key of tags that are defined without key, as imported by the tag syncer without tag-split-key.
+const DefaultTagKey = "tag"
+
+type TagRule = base.BinaryExpression[*TagComparison]
+
+// TagComparison matches data objects that have a tag with the given key and value.
+type TagComparison struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+func (c *TagComparison) Validate(_ context.Context) error {
+	if c.Key == "" {
+		return errors.New("tag key is empty")
+	}
+
+	if c.Value == "" {
+		return fmt.Errorf("value of tag %q is empty", c.Key)
+	}
+
+	return nil
+}
+
+func (c *TagComparison) Accept(ctx context.Context, visitor base.Visitor) error {
+	err := visitor.EnterExpressionElement(ctx, c)
+	if err != nil {
+		return fmt.Errorf("enter tag comparison: %w", err)
+	}
+
+	defer visitor.LeaveExpressionElement(ctx, c)
+
+	err = visitor.Literal(base.CtxExtendPathAndSetElement(ctx, "key", c), c.Key)
+	if err != nil {
+		return fmt.Errorf("key: %w", err)
+	}
+
+	err = visitor.Literal(base.CtxExtendPathAndSetElement(ctx, "value", c), c.Value)
+	if err != nil {
+		return fmt.Errorf("value: %w", err)
+	}
+
+	return nil
+}
+
+func (c *TagComparison) ToGql() (base.BinaryExpressionUnion, error) {
+	return c, nil
+}
+
+func (c *TagComparison) IsBinaryExpression() {}
+
+// ParseTagRule parses a boolean expression over tags, e.g. `domain:finance and not (pii = true or tag = deprecated)`.
+// A tag is matched by `<key>:<value>` or `<key> = <value>`. A tag without key, e.g. `pii`, matches the value of the default tag key.
+func ParseTagRule(ctx context.Context, input string) (*TagRule, error) {
+	rule, err := parse(input, func(c *Comparison) (*TagComparison, error) {
+		switch c.Operator {
+		case "":
+			return &TagComparison{Key: DefaultTagKey, Value: c.Left.Value}, nil
+		case ":", "=", "==":
+			return &TagComparison{Key: c.Left.Value, Value: c.Right.Value}, nil
+		default:
+			return nil, fmt.Errorf("unsupported tag operator %q, expected ':' or '='", c.Operator)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = rule.Validate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpression, err)
+	}
+
+	return rule, nil
+}
+
+// MatchTagRule returns true if the tags, as returned by hasTag, match the tag rule.
+func MatchTagRule(rule *TagRule, hasTag func(key string, value string) bool) bool {
+	return evaluate(rule, func(c *TagComparison) bool {
+		return hasTag(c.Key, c.Value)
+	})
+}
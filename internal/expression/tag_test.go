@@ -0,0 +1,144 @@
+package expression
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raito-io/bexpression/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagRule(t *testing.T) {
+	tag := func(key, value string) base.BinaryExpression[*TagComparison] {
+		return base.BinaryExpression[*TagComparison]{Comparison: &TagComparison{Key: key, Value: value}}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    *TagRule
+		wantErr string
+	}{
+		{
+			name:  "single tag",
+			input: "domain:finance",
+			want:  &TagRule{Comparison: &TagComparison{Key: "domain", Value: "finance"}},
+		},
+		{
+			name:  "tag without key",
+			input: "pii",
+			want:  &TagRule{Comparison: &TagComparison{Key: DefaultTagKey, Value: "pii"}},
+		},
+		{
+			name:  "and takes precedence over or",
+			input: `domain = "finance" OR domain:sales and not pii`,
+			want: &TagRule{Aggregator: &base.Aggregator[*TagComparison]{
+				Operator: base.AggregatorOperatorOr,
+				Operands: []base.BinaryExpression[*TagComparison]{
+					tag("domain", "finance"),
+					{Aggregator: &base.Aggregator[*TagComparison]{
+						Operator: base.AggregatorOperatorAnd,
+						Operands: []base.BinaryExpression[*TagComparison]{
+							tag("domain", "sales"),
+							{UnaryExpression: &base.UnaryExpression[*TagComparison]{Operator: base.UnaryOperatorNot, Operand: tag(DefaultTagKey, "pii")}},
+						},
+					}},
+				},
+			}},
+		},
+		{
+			name:  "parentheses and quoted values",
+			input: `(domain:finance or domain:'sales and marketing') and tier:gold`,
+			want: &TagRule{Aggregator: &base.Aggregator[*TagComparison]{
+				Operator: base.AggregatorOperatorAnd,
+				Operands: []base.BinaryExpression[*TagComparison]{
+					{Aggregator: &base.Aggregator[*TagComparison]{
+						Operator: base.AggregatorOperatorOr,
+						Operands: []base.BinaryExpression[*TagComparison]{tag("domain", "finance"), tag("domain", "sales and marketing")},
+					}},
+					tag("tier", "gold"),
+				},
+			}},
+		},
+		{
+			name:    "unsupported operator",
+			input:   "tier >= gold",
+			wantErr: `invalid expression: position 0: unsupported tag operator ">=", expected ':' or '='`,
+		},
+		{
+			name:    "missing closing parenthesis",
+			input:   "(domain:finance or pii",
+			wantErr: "invalid expression: expected ')' at position 22, got end of expression",
+		},
+		{
+			name:    "dangling operator",
+			input:   "domain:finance and",
+			wantErr: "invalid expression: unexpected end of expression at position 18",
+		},
+		{
+			name:    "empty value",
+			input:   "domain:''",
+			wantErr: `invalid expression: comparison: value of tag "domain" is empty`,
+		},
+		{
+			name:    "unterminated string",
+			input:   "domain:'finance",
+			wantErr: "invalid expression: unterminated string at position 7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTagRule(context.Background(), tt.input)
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatchTagRule(t *testing.T) {
+	tags := map[string][]string{
+		DefaultTagKey: {"pii", "daily"},
+		"domain":      {"finance"},
+	}
+
+	hasTag := func(key string, value string) bool {
+		for _, v := range tags[key] {
+			if v == value {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "pii", want: true},
+		{input: "hourly", want: false},
+		{input: "domain:finance and pii", want: true},
+		{input: "domain:sales or daily", want: true},
+		{input: "domain:finance and not pii", want: false},
+		{input: "not (domain:sales or hourly)", want: true},
+		{input: "false or domain = finance", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rule, err := ParseTagRule(context.Background(), tt.input)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, MatchTagRule(rule, hasTag))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Catalog contains the column types of the relations in the warehouse, as generated by `dbt docs generate` in catalog.json.
+type Catalog struct {
+	Nodes   map[string]CatalogTable `json:"nodes"`
+	Sources map[string]CatalogTable `json:"sources"`
+}
+
+type CatalogTable struct {
+	UniqueId string                   `json:"unique_id"`
+	Columns  map[string]CatalogColumn `json:"columns"`
+}
+
+type CatalogColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// LoadCatalogs loads and combines the catalogs defined in location. Multiple catalogs can be separated by a comma, e.g. one per project of a mesh.
+// Catalogs are loaded from the same forms of locations as manifests, a dbt Cloud location downloads the catalog.json artifact of the run.
+// The checksum option is not verified. Without location, nil is returned.
+func LoadCatalogs(ctx context.Context, location string, ops ...func(options *LoadOptions)) (*Catalog, error) {
+	if strings.TrimSpace(location) == "" {
+		return nil, nil
+	}
+
+	locations, err := ExpandLocations(location)
+	if err != nil {
+		return nil, err
+	}
+
+	options := newLoadOptions(ops...)
+	options.artifact = dbtCloudCatalogArtifact
+
+	result := &Catalog{Nodes: make(map[string]CatalogTable), Sources: make(map[string]CatalogTable)}
+
+	for _, l := range locations {
+		catalog, loadErr := loadCatalog(ctx, l, options)
+		if loadErr != nil {
+			return nil, fmt.Errorf("load catalog %s: %w", l, loadErr)
+		}
+
+		for uniqueId, table := range catalog.Nodes {
+			result.Nodes[uniqueId] = table
+		}
+
+		for uniqueId, table := range catalog.Sources {
+			result.Sources[uniqueId] = table
+		}
+	}
+
+	return result, nil
+}
+
+func loadCatalog(ctx context.Context, location string, options *LoadOptions) (*Catalog, error) {
+	src, err := newSource(location, options)
+	if err != nil {
+		return nil, err
+	}
+
+	content, _, _, err := src.fetch(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := decompress(content, options.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog Catalog
+
+	err = json.Unmarshal(jsonBytes, &catalog)
+	if err != nil {
+		return nil, fmt.Errorf("parse catalog: %w", err)
+	}
+
+	return &catalog, nil
+}
+
+// ColumnType returns the type of the column (ignoring the casing) of the node or source with the given unique id.
+func (c *Catalog) ColumnType(uniqueId string, column string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	table, found := c.Nodes[uniqueId]
+	if !found {
+		table, found = c.Sources[uniqueId]
+	}
+
+	if !found {
+		return "", false
+	}
+
+	if catalogColumn, columnFound := table.Columns[column]; columnFound && catalogColumn.Type != "" {
+		return catalogColumn.Type, true
+	}
+
+	for key, catalogColumn := range table.Columns {
+		name := catalogColumn.Name
+		if name == "" {
+			name = key
+		}
+
+		if strings.EqualFold(name, column) && catalogColumn.Type != "" {
+			return catalogColumn.Type, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCatalogs(t *testing.T) {
+	catalog, err := LoadCatalogs(context.Background(), "testdata/catalog.json")
+	require.NoError(t, err)
+
+	tests := []struct {
+		uniqueId string
+		column   string
+		want     string
+		wantOk   bool
+	}{
+		{uniqueId: "model.jaffle_shop.customers", column: "email", want: "STRING", wantOk: true},
+		{uniqueId: "model.jaffle_shop.customers", column: "CUSTOMER_ID", want: "INT64", wantOk: true},
+		{uniqueId: "source.jaffle_shop.raw.payments", column: "amount", want: "NUMERIC", wantOk: true},
+		{uniqueId: "model.jaffle_shop.customers", column: "unknown"},
+		{uniqueId: "model.jaffle_shop.orders", column: "email"},
+	}
+
+	for _, tt := range tests {
+		got, ok := catalog.ColumnType(tt.uniqueId, tt.column)
+		assert.Equal(t, tt.wantOk, ok, "%s.%s", tt.uniqueId, tt.column)
+		assert.Equal(t, tt.want, got, "%s.%s", tt.uniqueId, tt.column)
+	}
+
+	t.Run("without location", func(t *testing.T) {
+		catalog, err := LoadCatalogs(context.Background(), "")
+		require.NoError(t, err)
+
+		_, ok := catalog.ColumnType("model.jaffle_shop.customers", "email")
+		assert.False(t, ok)
+	})
+
+	t.Run("dbt Cloud", func(t *testing.T) {
+		var latestRunId atomic.Int64
+		var downloads atomic.Int32
+
+		latestRunId.Store(1001)
+
+		server := newDbtCloudStub(t, &latestRunId, &downloads)
+
+		catalog, err := LoadCatalogs(context.Background(), DbtCloudLocation("42", "7"), WithDbtCloudBaseUrl(server.URL), WithDbtCloudToken("dbt-token"))
+		require.NoError(t, err)
+
+		dataType, ok := catalog.ColumnType("model.jaffle_shop.customers", "email")
+		assert.True(t, ok)
+		assert.Equal(t, "STRING", dataType)
+		assert.Zero(t, downloads.Load())
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		var authorization string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+
+			http.ServeFile(w, r, "testdata/catalog.json")
+		}))
+		defer server.Close()
+
+		_, err := LoadCatalogs(context.Background(), server.URL+"/catalog.json", WithBearerToken("catalog-token"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "Bearer catalog-token", authorization)
+	})
+
+	t.Run("missing catalog", func(t *testing.T) {
+		_, err := LoadCatalogs(context.Background(), "testdata/missing_catalog.json")
+		assert.ErrorContains(t, err, "load catalog testdata/missing_catalog.json")
+	})
+}
@@ -0,0 +1,187 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	DbtCloudScheme         = "dbt-cloud://"
+	DefaultDbtCloudBaseUrl = "https://cloud.getdbt.com"
+
+	dbtCloudManifestArtifact = "manifest.json"
+	dbtCloudCatalogArtifact  = "catalog.json"
+	dbtCloudRunStatusSuccess = 10
+)
+
+// DbtCloudLocation returns the manifest location of the latest successful run of a dbt Cloud job.
+func DbtCloudLocation(accountId string, jobId string) string {
+	return fmt.Sprintf("%s%s/jobs/%s", DbtCloudScheme, accountId, jobId)
+}
+
+// DbtCloudRunLocation returns the manifest location of a specific dbt Cloud run.
+func DbtCloudRunLocation(accountId string, runId string) string {
+	return fmt.Sprintf("%s%s/runs/%s", DbtCloudScheme, accountId, runId)
+}
+
+func WithDbtCloudToken(token string) func(options *LoadOptions) {
+	return func(options *LoadOptions) {
+		options.DbtCloudToken = token
+	}
+}
+
+func WithDbtCloudBaseUrl(baseUrl string) func(options *LoadOptions) {
+	return func(options *LoadOptions) {
+		options.DbtCloudBaseUrl = baseUrl
+	}
+}
+
+// dbtCloudSource downloads the manifest (or catalog) artifact of a run via the dbt Cloud Administrative API v2.
+// The location is either `dbt-cloud://<account-id>/jobs/<job-id>` or `dbt-cloud://<account-id>/runs/<run-id>`.
+type dbtCloudSource struct {
+	location  string
+	accountId string
+	jobId     string
+	runId     string
+	options   *LoadOptions
+}
+
+func newDbtCloudSource(location string, options *LoadOptions) (*dbtCloudSource, error) {
+	parts := strings.Split(strings.TrimPrefix(location, DbtCloudScheme), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("invalid dbt Cloud location %q: expected %s<account-id>/jobs/<job-id> or %s<account-id>/runs/<run-id>", location, DbtCloudScheme, DbtCloudScheme)
+	}
+
+	result := &dbtCloudSource{
+		location:  location,
+		accountId: parts[0],
+		options:   options,
+	}
+
+	switch parts[1] {
+	case "jobs":
+		result.jobId = parts[2]
+	case "runs":
+		result.runId = parts[2]
+	default:
+		return nil, fmt.Errorf("invalid dbt Cloud location %q: unknown resource %q", location, parts[1])
+	}
+
+	return result, nil
+}
+
+func (s *dbtCloudSource) key() string {
+	return s.location
+}
+
+// fetch downloads the artifact of the run. The run id is used as version, so an unchanged latest successful run is not downloaded again.
+func (s *dbtCloudSource) fetch(ctx context.Context, cached *cacheEntry) ([]byte, string, bool, error) {
+	if s.options.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.options.Timeout)
+		defer cancel()
+	}
+
+	runId := s.runId
+
+	if runId == "" {
+		var err error
+
+		runId, err = s.latestSuccessfulRun(ctx)
+		if err != nil {
+			return nil, "", false, err
+		}
+	}
+
+	if cached != nil && cached.version == runId {
+		return nil, runId, true, nil
+	}
+
+	response, err := s.get(ctx, fmt.Sprintf("accounts/%s/runs/%s/artifacts/%s", url.PathEscape(s.accountId), url.PathEscape(runId), s.options.artifact), nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("download %s of dbt Cloud run %s: %w", s.options.artifact, runId, err)
+	}
+
+	defer response.Body.Close()
+
+	content, err := readLimited(response.Body, s.options.MaxSize)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("download %s of dbt Cloud run %s: %w", s.options.artifact, runId, err)
+	}
+
+	return content, runId, false, nil
+}
+
+func (s *dbtCloudSource) latestSuccessfulRun(ctx context.Context) (string, error) {
+	query := url.Values{}
+	query.Set("job_definition_id", s.jobId)
+	query.Set("status", strconv.Itoa(dbtCloudRunStatusSuccess))
+	query.Set("order_by", "-finished_at")
+	query.Set("limit", "1")
+
+	response, err := s.get(ctx, fmt.Sprintf("accounts/%s/runs/", url.PathEscape(s.accountId)), query)
+	if err != nil {
+		return "", fmt.Errorf("list runs of dbt Cloud job %s: %w", s.jobId, err)
+	}
+
+	defer response.Body.Close()
+
+	var runs struct {
+		Data []struct {
+			Id int64 `json:"id"`
+		} `json:"data"`
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&runs)
+	if err != nil {
+		return "", fmt.Errorf("parse runs of dbt Cloud job %s: %w", s.jobId, err)
+	}
+
+	if len(runs.Data) == 0 {
+		return "", fmt.Errorf("no successful run found for dbt Cloud job %s", s.jobId)
+	}
+
+	return strconv.FormatInt(runs.Data[0].Id, 10), nil
+}
+
+func (s *dbtCloudSource) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	baseUrl := s.options.DbtCloudBaseUrl
+	if baseUrl == "" {
+		baseUrl = DefaultDbtCloudBaseUrl
+	}
+
+	requestUrl := fmt.Sprintf("%s/api/v2/%s", strings.TrimSuffix(baseUrl, "/"), path)
+	if len(query) > 0 {
+		requestUrl += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	request.Header.Set("Accept", "application/json")
+
+	if s.options.DbtCloudToken != "" {
+		request.Header.Set("Authorization", "Bearer "+s.options.DbtCloudToken)
+	}
+
+	response, err := s.options.HttpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("request %s: %w", path, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+
+		return nil, fmt.Errorf("request %s: unexpected status %s", path, response.Status)
+	}
+
+	return response, nil
+}
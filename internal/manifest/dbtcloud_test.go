@@ -0,0 +1,113 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDbtCloudStub(t *testing.T, latestRunId *atomic.Int64, downloads *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v2/accounts/42/runs/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("job_definition_id") != "7" || query.Get("status") != "10" || query.Get("order_by") != "-finished_at" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"status": {"code": 200, "is_success": true}, "data": [{"id": %d, "job_definition_id": 7, "status": 10}]}`, latestRunId.Load())
+	})
+
+	mux.HandleFunc("GET /api/v2/accounts/42/runs/{runId}/artifacts/manifest.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("runId") == "404" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		downloads.Add(1)
+
+		_, _ = w.Write([]byte(testManifest))
+	})
+
+	mux.HandleFunc("GET /api/v2/accounts/42/runs/{runId}/artifacts/catalog.json", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/catalog.json")
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer dbt-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestParser_LoadManifest_DbtCloud(t *testing.T) {
+	latestRunId := atomic.Int64{}
+	latestRunId.Store(1001)
+
+	downloads := atomic.Int32{}
+
+	server := newDbtCloudStub(t, &latestRunId, &downloads)
+
+	ctx := context.Background()
+	options := []func(options *LoadOptions){WithDbtCloudBaseUrl(server.URL + "/"), WithDbtCloudToken("dbt-token")}
+
+	t.Run("latest successful run of job", func(t *testing.T) {
+		p := NewManifestParser()
+
+		m, err := p.LoadManifest(ctx, DbtCloudLocation("42", "7"), options...)
+		require.NoError(t, err)
+		assert.Equal(t, "project1", m.Metadata.ProjectName)
+		assert.Equal(t, int32(1), downloads.Load())
+
+		cached, err := p.LoadManifest(ctx, DbtCloudLocation("42", "7"), options...)
+		require.NoError(t, err)
+		assert.Same(t, m, cached)
+		assert.Equal(t, int32(1), downloads.Load(), "manifest of the same run should not be downloaded again")
+
+		latestRunId.Store(1002)
+
+		_, err = p.LoadManifest(ctx, DbtCloudLocation("42", "7"), options...)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), downloads.Load(), "manifest of a new run should be downloaded")
+	})
+
+	t.Run("specific run", func(t *testing.T) {
+		m, err := NewManifestParser().LoadManifest(ctx, DbtCloudRunLocation("42", "999"), options...)
+		require.NoError(t, err)
+		assert.Equal(t, "project1", m.Metadata.ProjectName)
+	})
+
+	t.Run("run without manifest", func(t *testing.T) {
+		_, err := NewManifestParser().LoadManifest(ctx, DbtCloudRunLocation("42", "404"), options...)
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := NewManifestParser().LoadManifest(ctx, DbtCloudLocation("42", "7"), WithDbtCloudBaseUrl(server.URL), WithDbtCloudToken("invalid"))
+		assert.ErrorContains(t, err, "401")
+	})
+
+	t.Run("invalid location", func(t *testing.T) {
+		_, err := NewManifestParser().LoadManifest(ctx, DbtCloudScheme+"42/environments/1", options...)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,102 @@
+package manifest
+
+// Parents returns the nodes the given node directly depends on. Dependencies that are not defined as node in the manifest (e.g. sources) are ignored, see LineageParents to include sources.
+func (m *Manifest) Parents(uniqueId string) []*Node {
+	node, found := m.Node(uniqueId)
+	if !found {
+		return nil
+	}
+
+	return m.nodes(node.DependsOn.Nodes)
+}
+
+// LineageParents returns the nodes and sources the given node directly depends on.
+func (m *Manifest) LineageParents(uniqueId string) []*Node {
+	node, found := m.Node(uniqueId)
+	if !found {
+		return nil
+	}
+
+	result := make([]*Node, 0, len(node.DependsOn.Nodes))
+
+	for _, parentId := range node.DependsOn.Nodes {
+		if parent, parentFound := m.Node(parentId); parentFound {
+			result = append(result, parent)
+		} else if source, sourceFound := m.Source(parentId); sourceFound {
+			result = append(result, source)
+		}
+	}
+
+	return result
+}
+
+// Children returns the nodes that directly depend on the given node, ordered by unique id.
+func (m *Manifest) Children(uniqueId string) []*Node {
+	return m.nodes(m.index().children[uniqueId])
+}
+
+// Ancestors returns all nodes upstream of the given node, up to the given depth. A negative depth is unlimited.
+func (m *Manifest) Ancestors(uniqueId string, depth int) []*Node {
+	return m.traverse(uniqueId, depth, m.Parents, nil)
+}
+
+// LineageAncestors returns all nodes and sources upstream of the given node, up to the given depth. A negative depth is unlimited.
+func (m *Manifest) LineageAncestors(uniqueId string, depth int) []*Node {
+	return m.traverse(uniqueId, depth, m.LineageParents, nil)
+}
+
+// Descendants returns all nodes downstream of the given node, up to the given depth. A negative depth is unlimited.
+func (m *Manifest) Descendants(uniqueId string, depth int) []*Node {
+	return m.traverse(uniqueId, depth, m.Children, nil)
+}
+
+// DescendantsWhere returns the nodes downstream of the given node, up to the given depth, that can be reached through nodes accepted by follow.
+// A node that is not accepted is excluded, together with the nodes that are only downstream through that node. A negative depth is unlimited.
+func (m *Manifest) DescendantsWhere(uniqueId string, depth int, follow func(node *Node) bool) []*Node {
+	return m.traverse(uniqueId, depth, m.Children, follow)
+}
+
+// traverse walks the graph breadth first, starting from (but excluding) the given node. If follow is defined, only nodes accepted by follow are visited.
+func (m *Manifest) traverse(uniqueId string, depth int, next func(uniqueId string) []*Node, follow func(node *Node) bool) []*Node {
+	var result []*Node
+
+	visited := map[string]struct{}{uniqueId: {}}
+	current := []string{uniqueId}
+
+	for level := 0; len(current) > 0 && (depth < 0 || level < depth); level++ {
+		var nextLevel []string
+
+		for _, id := range current {
+			for _, node := range next(id) {
+				if _, found := visited[node.UniqueId]; found {
+					continue
+				}
+
+				visited[node.UniqueId] = struct{}{}
+
+				if follow != nil && !follow(node) {
+					continue
+				}
+
+				result = append(result, node)
+				nextLevel = append(nextLevel, node.UniqueId)
+			}
+		}
+
+		current = nextLevel
+	}
+
+	return result
+}
+
+func (m *Manifest) nodes(uniqueIds []string) []*Node {
+	result := make([]*Node, 0, len(uniqueIds))
+
+	for _, uniqueId := range uniqueIds {
+		if node, found := m.Node(uniqueId); found {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,156 @@
+package manifest
+
+import (
+	"sort"
+	"strings"
+)
+
+// DataObjectResourceTypes are the node resource types that are materialized as data objects.
+var DataObjectResourceTypes = []string{"model", "seed", "snapshot"}
+
+type index struct {
+	byUniqueId     map[string]*Node
+	byRelation     map[string][]*Node
+	byResourceType map[string][]*Node
+	children       map[string][]string
+	sources        map[string]*Node
+}
+
+// RelationFullname returns the fully qualified name of the relation the node is materialized in.
+func (n *Node) RelationFullname() string {
+	identifier := n.Alias
+	if identifier == "" {
+		identifier = n.Name
+	}
+
+	return strings.Join([]string{n.Database, n.Schema, identifier}, ".")
+}
+
+// Node returns the node with the given unique id.
+func (m *Manifest) Node(uniqueId string) (*Node, bool) {
+	node, found := m.index().byUniqueId[uniqueId]
+
+	return node, found
+}
+
+// Source returns the source with the given unique id. Sources are not part of the nodes of the manifest.
+func (m *Manifest) Source(uniqueId string) (*Node, bool) {
+	source, found := m.index().sources[uniqueId]
+
+	return source, found
+}
+
+// NodesByRelation returns all nodes that are materialized in the relation with the given fullname.
+func (m *Manifest) NodesByRelation(fullname string) []*Node {
+	return m.index().byRelation[fullname]
+}
+
+// NodesOfType returns all nodes of the given resource types, ordered by unique id.
+func (m *Manifest) NodesOfType(resourceTypes ...string) []*Node {
+	idx := m.index()
+
+	if len(resourceTypes) == 1 {
+		return idx.byResourceType[resourceTypes[0]]
+	}
+
+	var result []*Node
+
+	for _, resourceType := range resourceTypes {
+		result = append(result, idx.byResourceType[resourceType]...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UniqueId < result[j].UniqueId
+	})
+
+	return result
+}
+
+// DataObjectNodes returns all nodes that are materialized as data objects, ordered by unique id.
+func (m *Manifest) DataObjectNodes() []*Node {
+	return m.NodesOfType(DataObjectResourceTypes...)
+}
+
+// OrderedExposures returns all exposures, ordered by unique id.
+func (m *Manifest) OrderedExposures() []*Exposure {
+	uniqueIds := make([]string, 0, len(m.Exposures))
+	for uniqueId := range m.Exposures {
+		uniqueIds = append(uniqueIds, uniqueId)
+	}
+
+	sort.Strings(uniqueIds)
+
+	result := make([]*Exposure, 0, len(uniqueIds))
+
+	for _, uniqueId := range uniqueIds {
+		exposure := m.Exposures[uniqueId]
+		if exposure.UniqueId == "" {
+			exposure.UniqueId = uniqueId
+		}
+
+		result = append(result, &exposure)
+	}
+
+	return result
+}
+
+func (m *Manifest) index() *index {
+	m.indexOnce.Do(func() {
+		m.idx = buildIndex(m.Nodes)
+		m.idx.sources = indexSources(m.Sources)
+	})
+
+	return m.idx
+}
+
+func buildIndex(nodes map[string]Node) *index {
+	idx := &index{
+		byUniqueId:     make(map[string]*Node, len(nodes)),
+		byRelation:     make(map[string][]*Node),
+		byResourceType: make(map[string][]*Node),
+		children:       make(map[string][]string),
+	}
+
+	uniqueIds := make([]string, 0, len(nodes))
+	for uniqueId := range nodes {
+		uniqueIds = append(uniqueIds, uniqueId)
+	}
+
+	sort.Strings(uniqueIds)
+
+	for _, uniqueId := range uniqueIds {
+		node := nodes[uniqueId]
+		if node.UniqueId == "" {
+			node.UniqueId = uniqueId
+		}
+
+		idx.byUniqueId[uniqueId] = &node
+		idx.byResourceType[node.ResourceType] = append(idx.byResourceType[node.ResourceType], &node)
+
+		if node.Database != "" || node.Schema != "" {
+			relationName := node.RelationFullname()
+			idx.byRelation[relationName] = append(idx.byRelation[relationName], &node)
+		}
+
+		for _, parent := range node.DependsOn.Nodes {
+			idx.children[parent] = append(idx.children[parent], uniqueId)
+		}
+	}
+
+	return idx
+}
+
+func indexSources(sources map[string]Node) map[string]*Node {
+	result := make(map[string]*Node, len(sources))
+
+	for uniqueId := range sources {
+		source := sources[uniqueId]
+		if source.UniqueId == "" {
+			source.UniqueId = uniqueId
+		}
+
+		result[uniqueId] = &source
+	}
+
+	return result
+}
@@ -1,67 +1,135 @@
 package manifest
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"sync"
 )
 
-var _manifestParser *parser
+var (
+	_manifestParser     *parser
+	_manifestParserOnce sync.Once
+)
 
 type Parser interface {
-	LoadManifest(path string) (*Manifest, error)
+	LoadManifest(ctx context.Context, location string, ops ...func(options *LoadOptions)) (*Manifest, error)
 }
 
+// GlobalManifestParser returns the parser shared by all syncers within the plugin process.
+// Sharing the parser ensures a manifest is only parsed once per run.
 func GlobalManifestParser() Parser {
-	if _manifestParser == nil {
-		_manifestParser = &parser{}
-	}
+	_manifestParserOnce.Do(func() {
+		_manifestParser = newParser()
+	})
 
 	return _manifestParser
 }
 
 func NewManifestParser() Parser {
-	return &parser{}
+	return newParser()
+}
+
+type cacheEntry struct {
+	version  string
+	checksum [sha256.Size]byte
+	manifest *Manifest
 }
 
 type parser struct {
-	isValid  bool
-	manifest Manifest
-	path     string
+	mutex sync.Mutex
+	cache map[string]*cacheEntry
 }
 
-func (m *parser) LoadManifest(path string) (*Manifest, error) {
-	filePath, err := filepath.Abs(path)
+func newParser() *parser {
+	return &parser{
+		cache: make(map[string]*cacheEntry),
+	}
+}
+
+// LoadManifest parses the manifest at the given location.
+// The location can be a local file path, a file:// or http(s):// url, or `-` to read the manifest from stdin.
+// Gzip and zstd compressed manifests are decompressed automatically.
+//
+// Parsed manifests are cached by location. The cache entry is reused as long as the source reports the manifest is not
+// modified (file modification time and size, http ETag), or when the content still has the same checksum.
+func (m *parser) LoadManifest(ctx context.Context, location string, ops ...func(options *LoadOptions)) (*Manifest, error) {
+	options := newLoadOptions(ops...)
+
+	src, err := newSource(location, options)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry := m.cache[src.key()]
+
+	content, version, notModified, err := src.fetch(ctx, entry)
 	if err != nil {
-		return nil, fmt.Errorf("absoluting path: %w", err)
+		return nil, err
 	}
 
-	if m.isValid && m.path == filePath {
-		return &m.manifest, nil
+	if notModified {
+		err = verifyChecksum(entry.checksum, options.Checksum)
+		if err != nil {
+			return nil, err
+		}
+
+		return entry.manifest, nil
 	}
 
-	err = m.loadDbtFile(filePath)
+	checksum := sha256.Sum256(content)
+
+	err = verifyChecksum(checksum, options.Checksum)
 	if err != nil {
 		return nil, err
 	}
 
-	m.isValid = true
-	m.path = filePath
+	if entry != nil && entry.checksum == checksum {
+		entry.version = version
 
-	return &m.manifest, nil
+		return entry.manifest, nil
+	}
+
+	jsonBytes, err := decompress(content, options.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := parseManifest(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	m.cache[src.key()] = &cacheEntry{
+		version:  version,
+		checksum: checksum,
+		manifest: manifest,
+	}
+
+	return manifest, nil
 }
 
-func (m *parser) loadDbtFile(dbtFilePath string) error {
-	jsonBytes, err := os.ReadFile(dbtFilePath)
+func parseManifest(jsonBytes []byte) (*Manifest, error) {
+	version, err := detectSchemaVersion(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Manifest
+
+	err = json.Unmarshal(jsonBytes, &result)
 	if err != nil {
-		return fmt.Errorf("reading dbt file: %w", err)
+		return nil, fmt.Errorf("parsing dbt file: %w", err)
 	}
 
-	err = json.Unmarshal(jsonBytes, &m.manifest)
+	err = adaptManifest(&result, version)
 	if err != nil {
-		return fmt.Errorf("parsing dbt file: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return &result, nil
 }
@@ -0,0 +1,125 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{
+  "metadata": {"dbt_schema_version": "https://schemas.getdbt.com/dbt/manifest/v11.json", "project_name": "project1"},
+  "nodes": {
+    "model.project1.orders": {"database": "db", "schema": "finance", "name": "orders", "alias": "orders", "resource_type": "model", "depends_on": {"nodes": ["source.project1.erp.orders", "seed.project1.countries"]}},
+    "model.project1.customers": {"database": "db", "schema": "finance", "name": "customers", "alias": "dim_customers", "resource_type": "model"},
+    "seed.project1.countries": {"database": "db", "schema": "seeds", "name": "countries", "resource_type": "seed"},
+    "test.project1.not_null_orders_id": {"database": "db", "schema": "finance", "name": "not_null_orders_id", "resource_type": "test"}
+  },
+  "sources": {
+    "source.project1.erp.orders": {"database": "raw", "schema": "erp", "name": "orders", "resource_type": "source", "tags": ["classification:pii"]}
+  }
+}`
+
+func TestParser_LoadManifest_Cache(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(testManifest), 0600))
+
+	p := NewManifestParser()
+
+	first, err := p.LoadManifest(context.Background(), manifestPath)
+	require.NoError(t, err)
+
+	second, err := p.LoadManifest(context.Background(), manifestPath)
+	require.NoError(t, err)
+	assert.Same(t, first, second, "unchanged file should return the cached manifest")
+
+	// Touching the file without changing the content keeps the cache valid
+	touchTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(manifestPath, touchTime, touchTime))
+
+	third, err := p.LoadManifest(context.Background(), manifestPath)
+	require.NoError(t, err)
+	assert.Same(t, first, third, "file with same checksum should return the cached manifest")
+
+	// Changing the content invalidates the cache
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`{"metadata": {"dbt_schema_version": "https://schemas.getdbt.com/dbt/manifest/v11.json", "project_name": "project2"}, "nodes": {}}`), 0600))
+	require.NoError(t, os.Chtimes(manifestPath, touchTime.Add(time.Minute), touchTime.Add(time.Minute)))
+
+	fourth, err := p.LoadManifest(context.Background(), manifestPath)
+	require.NoError(t, err)
+	assert.NotSame(t, first, fourth)
+	assert.Equal(t, "project2", fourth.Metadata.ProjectName)
+}
+
+func TestParser_LoadManifest_Errors(t *testing.T) {
+	invalidPath := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalidPath, []byte(`{"nodes": [`), 0600))
+
+	p := NewManifestParser()
+
+	_, err := p.LoadManifest(context.Background(), filepath.Join(t.TempDir(), "does-not-exist.json"))
+	assert.Error(t, err)
+
+	_, err = p.LoadManifest(context.Background(), invalidPath)
+	assert.Error(t, err)
+}
+
+func TestManifest_Indexes(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(testManifest), 0600))
+
+	m, err := NewManifestParser().LoadManifest(context.Background(), manifestPath)
+	require.NoError(t, err)
+
+	node, found := m.Node("model.project1.orders")
+	require.True(t, found)
+	assert.Equal(t, "orders", node.Name)
+
+	_, found = m.Node("model.project1.unknown")
+	assert.False(t, found)
+
+	customers := m.NodesByRelation("db.finance.dim_customers")
+	require.Len(t, customers, 1)
+	assert.Equal(t, "model.project1.customers", customers[0].UniqueId)
+
+	seeds := m.NodesByRelation("db.seeds.countries")
+	require.Len(t, seeds, 1)
+	assert.Equal(t, "seed.project1.countries", seeds[0].UniqueId)
+
+	assert.Len(t, m.NodesOfType("test"), 1)
+	assert.Empty(t, m.NodesOfType("snapshot"))
+
+	dataObjectNodes := m.DataObjectNodes()
+	uniqueIds := make([]string, 0, len(dataObjectNodes))
+
+	for _, n := range dataObjectNodes {
+		uniqueIds = append(uniqueIds, n.UniqueId)
+	}
+
+	assert.Equal(t, []string{"model.project1.customers", "model.project1.orders", "seed.project1.countries"}, uniqueIds)
+
+	source, found := m.Source("source.project1.erp.orders")
+	require.True(t, found)
+	assert.Equal(t, []string{"classification:pii"}, source.Tags)
+
+	_, found = m.Node("source.project1.erp.orders")
+	assert.False(t, found)
+
+	assert.Equal(t, []string{"seed.project1.countries"}, nodeIds(m.Parents("model.project1.orders")))
+	assert.Equal(t, []string{"source.project1.erp.orders", "seed.project1.countries"}, nodeIds(m.LineageParents("model.project1.orders")))
+	assert.Equal(t, []string{"source.project1.erp.orders", "seed.project1.countries"}, nodeIds(m.LineageAncestors("model.project1.orders", -1)))
+}
+
+func nodeIds(nodes []*Node) []string {
+	result := make([]string, 0, len(nodes))
+
+	for _, n := range nodes {
+		result = append(result, n.UniqueId)
+	}
+
+	return result
+}
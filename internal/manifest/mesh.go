@@ -0,0 +1,206 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	locationSeparator = ","
+	publicAccess      = "public"
+	privateAccess     = "private"
+)
+
+var (
+	ErrRefNotFound      = errors.New("ref not found")
+	ErrRefNotAccessible = errors.New("ref not accessible")
+)
+
+// Mesh combines the manifests of multiple dbt projects (dbt Mesh) that reference each other.
+type Mesh struct {
+	Manifests []*Manifest
+
+	byProject map[string]*Manifest
+}
+
+func NewMesh(manifests ...*Manifest) (*Mesh, error) {
+	mesh := &Mesh{
+		Manifests: manifests,
+		byProject: make(map[string]*Manifest, len(manifests)),
+	}
+
+	for _, m := range manifests {
+		if _, found := mesh.byProject[m.Metadata.ProjectName]; found {
+			return nil, fmt.Errorf("project %q is defined in multiple manifests", m.Metadata.ProjectName)
+		}
+
+		mesh.byProject[m.Metadata.ProjectName] = m
+	}
+
+	return mesh, nil
+}
+
+// LoadMesh loads all manifests defined in location. Multiple manifests can be separated by a comma, and local paths
+// can contain glob patterns. When a checksum is defined, it should contain one comma separated checksum per manifest,
+// in the order of the expanded locations.
+func LoadMesh(ctx context.Context, parser Parser, location string, ops ...func(options *LoadOptions)) (*Mesh, error) {
+	locations, err := ExpandLocations(location)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums, err := splitChecksums(newLoadOptions(ops...).Checksum, len(locations))
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]*Manifest, 0, len(locations))
+
+	for i, l := range locations {
+		m, loadErr := parser.LoadManifest(ctx, l, append(slices.Clone(ops), WithChecksum(checksums[i]))...)
+		if loadErr != nil {
+			return nil, fmt.Errorf("load manifest %s: %w", l, loadErr)
+		}
+
+		manifests = append(manifests, m)
+	}
+
+	return NewMesh(manifests...)
+}
+
+// splitChecksums splits a comma separated list of checksums in one checksum per manifest location.
+func splitChecksums(checksum string, locations int) ([]string, error) {
+	result := make([]string, locations)
+
+	if checksum == "" {
+		return result, nil
+	}
+
+	checksums := strings.Split(checksum, locationSeparator)
+	if len(checksums) != locations {
+		return nil, fmt.Errorf("expected one checksum per manifest, got %d checksums for %d manifests", len(checksums), locations)
+	}
+
+	for i := range checksums {
+		result[i] = strings.TrimSpace(checksums[i])
+	}
+
+	return result, nil
+}
+
+// ExpandLocations splits a comma separated list of manifest locations and expands glob patterns of local paths.
+func ExpandLocations(location string) ([]string, error) {
+	var result []string
+
+	for _, l := range strings.Split(location, locationSeparator) {
+		l = strings.TrimSpace(l)
+
+		switch {
+		case l == "":
+			continue
+		case !isLocalPath(l) || !strings.ContainsAny(l, "*?["):
+			result = append(result, l)
+		default:
+			matches, err := filepath.Glob(strings.TrimPrefix(l, fileScheme))
+			if err != nil {
+				return nil, fmt.Errorf("expand manifest location %q: %w", l, err)
+			}
+
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no manifest found matching %q", l)
+			}
+
+			sort.Strings(matches)
+			result = append(result, matches...)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("no manifest location defined")
+	}
+
+	return result, nil
+}
+
+func isLocalPath(location string) bool {
+	return location != StdinLocation && !strings.HasPrefix(location, httpScheme) && !strings.HasPrefix(location, httpsScheme) && !strings.HasPrefix(location, DbtCloudScheme)
+}
+
+// Project returns the manifest of the given project.
+func (m *Mesh) Project(projectName string) (*Manifest, bool) {
+	result, found := m.byProject[projectName]
+
+	return result, found
+}
+
+// Node searches a node by unique id in all manifests of the mesh.
+func (m *Mesh) Node(uniqueId string) (*Node, *Manifest, bool) {
+	for _, manifest := range m.Manifests {
+		if node, found := manifest.Node(uniqueId); found {
+			return node, manifest, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// ResolveRef resolves a model reference from within a project.
+// The reference is either `<model>` or `<project or package>.<model>`, similar to the one and two argument form of the dbt ref function.
+// Models of other projects of the mesh can only be referenced if they have public access.
+// Models of installed packages can be referenced unless they have private access, as in dbt.
+func (m *Mesh) ResolveRef(from *Manifest, ref string) (*Node, error) {
+	packageName := from.Metadata.ProjectName
+	modelName := ref
+
+	if idx := strings.Index(ref, "."); idx >= 0 {
+		packageName = ref[:idx]
+		modelName = ref[idx+1:]
+	}
+
+	node := findModel(from, packageName, modelName)
+	if node == nil {
+		target, found := m.byProject[packageName]
+		if !found || target == from {
+			return nil, fmt.Errorf("%w: %q in project %q", ErrRefNotFound, ref, from.Metadata.ProjectName)
+		}
+
+		node = findModel(target, packageName, modelName)
+		if node == nil {
+			return nil, fmt.Errorf("%w: %q in project %q", ErrRefNotFound, ref, packageName)
+		}
+	}
+
+	if packageName == from.Metadata.ProjectName {
+		return node, nil
+	}
+
+	if _, meshProject := m.byProject[packageName]; meshProject && node.Access != publicAccess {
+		return nil, fmt.Errorf("%w: model %q of project %q has %s access, only public models can be referenced from project %q", ErrRefNotAccessible, modelName, packageName, node.Access, from.Metadata.ProjectName)
+	}
+
+	if node.Access == privateAccess {
+		return nil, fmt.Errorf("%w: model %q of package %q has private access", ErrRefNotAccessible, modelName, packageName)
+	}
+
+	return node, nil
+}
+
+// findModel returns the model with the given name within a package. For versioned models the latest version is returned.
+func findModel(m *Manifest, packageName string, modelName string) *Node {
+	for _, node := range m.NodesOfType("model") {
+		if node.PackageName != packageName || node.Name != modelName {
+			continue
+		}
+
+		if node.IsLatestVersion() {
+			return node
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMeshTestManifest(projectName string, nodes ...Node) *Manifest {
+	m := &Manifest{
+		Metadata: Metadata{ProjectName: projectName},
+		Nodes:    make(map[string]Node, len(nodes)),
+	}
+
+	for _, node := range nodes {
+		m.Nodes[node.UniqueId] = node
+	}
+
+	return m
+}
+
+func TestExpandLocations(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"b_manifest.json", "a_manifest.json", "other.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600))
+	}
+
+	tests := []struct {
+		name     string
+		location string
+		want     []string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "single location",
+			location: "manifest.json",
+			want:     []string{"manifest.json"},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "comma separated",
+			location: "core/manifest.json, https://example.com/manifest.json ,-",
+			want:     []string{"core/manifest.json", "https://example.com/manifest.json", "-"},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "glob",
+			location: filepath.Join(dir, "*_manifest.json"),
+			want:     []string{filepath.Join(dir, "a_manifest.json"), filepath.Join(dir, "b_manifest.json")},
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "glob without match",
+			location: filepath.Join(dir, "*.yml"),
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "empty",
+			location: " , ",
+			wantErr:  assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandLocations(tt.location)
+			if !tt.wantErr(t, err) {
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadMesh_Checksums(t *testing.T) {
+	dir := t.TempDir()
+
+	core := []byte(testManifest)
+	finance := []byte(strings.ReplaceAll(testManifest, "project1", "finance"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "core.json"), core, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "finance.json"), finance, 0600))
+
+	location := filepath.Join(dir, "core.json") + "," + filepath.Join(dir, "finance.json")
+	ctx := context.Background()
+
+	t.Run("one checksum per manifest", func(t *testing.T) {
+		mesh, err := LoadMesh(ctx, NewManifestParser(), location, WithChecksum(sha256Hex(core)+", sha256:"+sha256Hex(finance)))
+		require.NoError(t, err)
+		assert.Len(t, mesh.Manifests, 2)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		_, err := LoadMesh(ctx, NewManifestParser(), location, WithChecksum(sha256Hex(finance)+","+sha256Hex(core)))
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("single checksum for multiple manifests", func(t *testing.T) {
+		_, err := LoadMesh(ctx, NewManifestParser(), location, WithChecksum(sha256Hex(core)))
+		assert.ErrorContains(t, err, "expected one checksum per manifest, got 1 checksums for 2 manifests")
+	})
+}
+
+func TestNewMesh_DuplicateProject(t *testing.T) {
+	_, err := NewMesh(newMeshTestManifest("core"), newMeshTestManifest("core"))
+	assert.Error(t, err)
+}
+
+func TestMesh_ResolveRef(t *testing.T) {
+	core := newMeshTestManifest("core",
+		Node{UniqueId: "model.core.customers", Name: "customers", PackageName: "core", ResourceType: "model", Access: "public"},
+		Node{UniqueId: "model.core.orders", Name: "orders", PackageName: "core", ResourceType: "model", Access: "protected"},
+		Node{UniqueId: "model.core.payments.v1", Name: "payments", PackageName: "core", ResourceType: "model", Access: "public", Version: "1", LatestVersion: "2"},
+		Node{UniqueId: "model.core.payments.v2", Name: "payments", PackageName: "core", ResourceType: "model", Access: "public", Version: "2", LatestVersion: "2"},
+	)
+	finance := newMeshTestManifest("finance",
+		Node{UniqueId: "model.finance.revenue", Name: "revenue", PackageName: "finance", ResourceType: "model", Access: "protected"},
+		Node{UniqueId: "model.finance_utils.calendar", Name: "calendar", PackageName: "finance_utils", ResourceType: "model", Access: "public"},
+		Node{UniqueId: "model.finance_utils.fx_rates", Name: "fx_rates", PackageName: "finance_utils", ResourceType: "model", Access: "private"},
+		Node{UniqueId: "model.finance_utils.budgets", Name: "budgets", PackageName: "finance_utils", ResourceType: "model", Access: "protected"},
+		Node{UniqueId: "model.core.order_items", Name: "order_items", PackageName: "core", ResourceType: "model", Access: "protected"},
+	)
+
+	mesh, err := NewMesh(core, finance)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		from     *Manifest
+		ref      string
+		wantNode string
+		wantErr  error
+	}{
+		{name: "same project", from: finance, ref: "revenue", wantNode: "model.finance.revenue"},
+		{name: "same project protected", from: core, ref: "orders", wantNode: "model.core.orders"},
+		{name: "same project with package", from: finance, ref: "finance.revenue", wantNode: "model.finance.revenue"},
+		{name: "other package public", from: finance, ref: "finance_utils.calendar", wantNode: "model.finance_utils.calendar"},
+		{name: "other package protected", from: finance, ref: "finance_utils.budgets", wantNode: "model.finance_utils.budgets"},
+		{name: "other package private", from: finance, ref: "finance_utils.fx_rates", wantErr: ErrRefNotAccessible},
+		{name: "other project protected in manifest", from: finance, ref: "core.order_items", wantErr: ErrRefNotAccessible},
+		{name: "other project public", from: finance, ref: "core.customers", wantNode: "model.core.customers"},
+		{name: "other project latest version", from: finance, ref: "core.payments", wantNode: "model.core.payments.v2"},
+		{name: "other project protected", from: finance, ref: "core.orders", wantErr: ErrRefNotAccessible},
+		{name: "unknown model", from: finance, ref: "core.unknown", wantErr: ErrRefNotFound},
+		{name: "unknown project", from: finance, ref: "marketing.campaigns", wantErr: ErrRefNotFound},
+		{name: "other project without package", from: finance, ref: "customers", wantErr: ErrRefNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := mesh.ResolveRef(tt.from, tt.ref)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNode, node.UniqueId)
+		})
+	}
+}
@@ -0,0 +1,429 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	selectorMethodTag          = "tag"
+	selectorMethodPath         = "path"
+	selectorMethodPackage      = "package"
+	selectorMethodFqn          = "fqn"
+	selectorMethodMaterialized = "config.materialized"
+	selectorMethodGroup        = "group"
+	selectorMethodAccess       = "access"
+	selectorMethodResourceType = "resource_type"
+
+	selectorWildcards = "*?["
+)
+
+var (
+	ErrInvalidSelector = errors.New("invalid selector")
+
+	selectorRegex = regexp.MustCompile(`^(@)?(\d*\+)?([^+@]+?)(\+\d*)?$`)
+)
+
+// Selection selects nodes of a manifest based on the dbt node selection syntax.
+// Selectors separated by whitespace are combined as union, selectors separated by a comma as intersection.
+// Nodes matching the exclude expression are removed from the selection.
+// Alternatively, a selection refers to a named selector defined in the selectors section of the manifest.
+type Selection struct {
+	include      selectionExpression
+	exclude      selectionExpression
+	selectorName string
+
+	selectExpression  string
+	excludeExpression string
+}
+
+// selectionExpression evaluates to the unique ids of the selected nodes.
+type selectionExpression interface {
+	evaluate(m *Manifest) map[string]struct{}
+}
+
+type unionExpression []selectionExpression
+
+type intersectionExpression []selectionExpression
+
+type differenceExpression struct {
+	include selectionExpression
+	exclude selectionExpression
+}
+
+// selector is a single selection criterion, e.g. `+tag:finance+2`.
+type selector struct {
+	method string
+	value  string
+
+	parents         bool
+	parentDepth     int
+	children        bool
+	childDepth      int
+	childrenParents bool // @ operator: descendants and all ancestors of those descendants
+}
+
+// ParseSelection parses a select and exclude expression. An empty select expression selects all nodes.
+func ParseSelection(selectExpression string, excludeExpression string) (*Selection, error) {
+	include, err := parseSelectorExpression(selectExpression)
+	if err != nil {
+		return nil, fmt.Errorf("parse select %q: %w", selectExpression, err)
+	}
+
+	exclude, err := parseSelectorExpression(excludeExpression)
+	if err != nil {
+		return nil, fmt.Errorf("parse exclude %q: %w", excludeExpression, err)
+	}
+
+	return &Selection{
+		include:           include,
+		exclude:           exclude,
+		selectExpression:  strings.Join(strings.Fields(selectExpression), " "),
+		excludeExpression: strings.Join(strings.Fields(excludeExpression), " "),
+	}, nil
+}
+
+// NamedSelection returns a selection using the named selector defined in the selectors section of each manifest.
+func NamedSelection(selectorName string) *Selection {
+	return &Selection{selectorName: selectorName}
+}
+
+// String returns the normalized definition of the selection, e.g. `select tag:finance; exclude orders_archive`.
+// An empty string is returned if the selection selects all nodes.
+func (s *Selection) String() string {
+	if s == nil {
+		return ""
+	}
+
+	if s.selectorName != "" {
+		return "selector " + s.selectorName
+	}
+
+	var parts []string
+
+	if s.selectExpression != "" {
+		parts = append(parts, "select "+s.selectExpression)
+	}
+
+	if s.excludeExpression != "" {
+		parts = append(parts, "exclude "+s.excludeExpression)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func parseSelectorExpression(expression string) (selectionExpression, error) {
+	var result unionExpression
+
+	for _, union := range strings.Fields(expression) {
+		var intersection intersectionExpression
+
+		for _, criterion := range strings.Split(union, ",") {
+			sel, err := parseSelector(criterion)
+			if err != nil {
+				return nil, err
+			}
+
+			intersection = append(intersection, sel)
+		}
+
+		result = append(result, intersection)
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	return result, nil
+}
+
+func parseSelector(criterion string) (*selector, error) {
+	matches := selectorRegex.FindStringSubmatch(criterion)
+	if matches == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSelector, criterion)
+	}
+
+	result := &selector{
+		parents:         matches[2] != "",
+		parentDepth:     operatorDepth(matches[2]),
+		children:        matches[4] != "",
+		childDepth:      operatorDepth(matches[4]),
+		childrenParents: matches[1] != "",
+	}
+
+	if result.childrenParents && (result.parents || result.children) {
+		return nil, fmt.Errorf("%w: %q, the @ operator can not be combined with the + operator", ErrInvalidSelector, criterion)
+	}
+
+	method, value, found := strings.Cut(matches[3], ":")
+
+	switch {
+	case !found && (strings.Contains(method, "/") || hasFileExtension(method)):
+		result.method, result.value = selectorMethodPath, method
+	case !found:
+		result.method, result.value = selectorMethodFqn, method
+	default:
+		result.method, result.value = method, value
+	}
+
+	err := result.validate()
+	if err != nil {
+		return nil, fmt.Errorf("%w in %q", err, criterion)
+	}
+
+	return result, nil
+}
+
+func (sel *selector) validate() error {
+	switch sel.method {
+	case selectorMethodTag, selectorMethodPath, selectorMethodPackage, selectorMethodFqn, selectorMethodMaterialized, selectorMethodGroup, selectorMethodAccess, selectorMethodResourceType:
+	default:
+		return fmt.Errorf("%w: unsupported selector method %q", ErrInvalidSelector, sel.method)
+	}
+
+	if sel.value == "" {
+		return fmt.Errorf("%w: selector method %q has no value", ErrInvalidSelector, sel.method)
+	}
+
+	return nil
+}
+
+// operatorDepth returns the depth of a graph operator (e.g. `2+` or `+2`). Without explicit depth the graph is traversed completely.
+func operatorDepth(operator string) int {
+	depth, err := strconv.Atoi(strings.Trim(operator, "+"))
+	if err != nil {
+		return -1
+	}
+
+	return depth
+}
+
+func hasFileExtension(value string) bool {
+	switch path.Ext(value) {
+	case ".sql", ".py", ".csv":
+		return true
+	default:
+		return false
+	}
+}
+
+// SelectDataObjectNodes returns all data object nodes selected by the selection, ordered by unique id.
+// A nil selection selects all data object nodes.
+func (m *Manifest) SelectDataObjectNodes(selection *Selection) ([]*Node, error) {
+	nodes := m.DataObjectNodes()
+
+	if selection == nil {
+		return nodes, nil
+	}
+
+	include, exclude := selection.include, selection.exclude
+
+	if selection.selectorName != "" {
+		expression, err := m.namedSelector(selection.selectorName)
+		if err != nil {
+			return nil, err
+		}
+
+		include, exclude = expression, nil
+	}
+
+	if include == nil && exclude == nil {
+		return nodes, nil
+	}
+
+	var included, excluded map[string]struct{}
+
+	if include != nil {
+		included = include.evaluate(m)
+	}
+
+	if exclude != nil {
+		excluded = exclude.evaluate(m)
+	}
+
+	result := make([]*Node, 0, len(nodes))
+
+	for _, node := range nodes {
+		if included != nil {
+			if _, found := included[node.UniqueId]; !found {
+				continue
+			}
+		}
+
+		if _, found := excluded[node.UniqueId]; found {
+			continue
+		}
+
+		result = append(result, node)
+	}
+
+	return result, nil
+}
+
+func (e unionExpression) evaluate(m *Manifest) map[string]struct{} {
+	result := make(map[string]struct{})
+
+	for _, expression := range e {
+		for uniqueId := range expression.evaluate(m) {
+			result[uniqueId] = struct{}{}
+		}
+	}
+
+	return result
+}
+
+func (e intersectionExpression) evaluate(m *Manifest) map[string]struct{} {
+	var result map[string]struct{}
+
+	for _, expression := range e {
+		selected := expression.evaluate(m)
+
+		if result == nil {
+			result = selected
+
+			continue
+		}
+
+		for uniqueId := range result {
+			if _, found := selected[uniqueId]; !found {
+				delete(result, uniqueId)
+			}
+		}
+	}
+
+	if result == nil {
+		return map[string]struct{}{}
+	}
+
+	return result
+}
+
+func (e *differenceExpression) evaluate(m *Manifest) map[string]struct{} {
+	result := e.include.evaluate(m)
+
+	for uniqueId := range e.exclude.evaluate(m) {
+		delete(result, uniqueId)
+	}
+
+	return result
+}
+
+// evaluate returns the unique ids of all nodes matching the selector, including the nodes selected by graph operators.
+func (sel *selector) evaluate(m *Manifest) map[string]struct{} {
+	result := make(map[string]struct{})
+
+	add := func(nodes []*Node) {
+		for _, node := range nodes {
+			result[node.UniqueId] = struct{}{}
+		}
+	}
+
+	for _, node := range m.index().byUniqueId {
+		if !sel.matches(m, node) {
+			continue
+		}
+
+		result[node.UniqueId] = struct{}{}
+
+		if sel.parents {
+			add(m.Ancestors(node.UniqueId, sel.parentDepth))
+		}
+
+		if sel.children || sel.childrenParents {
+			descendants := m.Descendants(node.UniqueId, sel.childDepth)
+			add(descendants)
+
+			if sel.childrenParents {
+				for _, descendant := range descendants {
+					add(m.Ancestors(descendant.UniqueId, -1))
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func (sel *selector) matches(m *Manifest, node *Node) bool {
+	switch sel.method {
+	case selectorMethodTag:
+		for _, tags := range [][]string{node.Tags, node.Config.Tags} {
+			for _, tag := range tags {
+				if wildcardMatch(sel.value, tag) {
+					return true
+				}
+			}
+		}
+
+		return false
+	case selectorMethodPath:
+		return pathMatch(sel.value, node.OriginalFilePath)
+	case selectorMethodPackage:
+		packageName := sel.value
+		if packageName == "this" {
+			packageName = m.Metadata.ProjectName
+		}
+
+		return wildcardMatch(packageName, node.PackageName)
+	case selectorMethodMaterialized:
+		return node.Config.Materialized != nil && wildcardMatch(sel.value, *node.Config.Materialized)
+	case selectorMethodGroup:
+		return wildcardMatch(sel.value, node.Group)
+	case selectorMethodAccess:
+		return wildcardMatch(sel.value, node.Access)
+	case selectorMethodResourceType:
+		return wildcardMatch(sel.value, node.ResourceType)
+	default:
+		return fqnMatch(sel.value, node)
+	}
+}
+
+// fqnMatch matches the selector against the name of the node, or as dot separated prefix of the fully qualified name.
+func fqnMatch(value string, node *Node) bool {
+	fqn := node.Fqn
+	if len(fqn) == 0 {
+		fqn = []string{node.PackageName, node.Name}
+	}
+
+	if wildcardMatch(value, fqn[len(fqn)-1]) || wildcardMatch(value, node.Name) {
+		return true
+	}
+
+	parts := strings.Split(value, ".")
+	if len(parts) > len(fqn) {
+		return false
+	}
+
+	for i, part := range parts {
+		if !wildcardMatch(part, fqn[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pathMatch matches the selector against the path of the node. A directory selects all nodes within that directory.
+func pathMatch(value string, nodePath string) bool {
+	value = path.Clean(value)
+
+	if wildcardMatch(value, nodePath) {
+		return true
+	}
+
+	return !strings.ContainsAny(value, selectorWildcards) && strings.HasPrefix(nodePath, value+"/")
+}
+
+func wildcardMatch(pattern string, value string) bool {
+	if !strings.ContainsAny(pattern, selectorWildcards) {
+		return pattern == value
+	}
+
+	matched, err := path.Match(pattern, value)
+
+	return err == nil && matched
+}
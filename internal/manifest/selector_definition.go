@@ -0,0 +1,186 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	definitionUnion        = "union"
+	definitionIntersection = "intersection"
+	definitionExclude      = "exclude"
+	definitionMethod       = "method"
+	definitionValue        = "value"
+	definitionSelector     = "selector"
+)
+
+// namedSelector parses the definition of the named selector.
+func (m *Manifest) namedSelector(name string) (selectionExpression, error) {
+	expression, err := parseSelectorDefinition(m.Selectors, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("selector %q of project %q: %w", name, m.Metadata.ProjectName, err)
+	}
+
+	return expression, nil
+}
+
+// parseSelectorDefinition parses a named selector. The visited selectors are tracked to detect cyclic references.
+func parseSelectorDefinition(selectors map[string]NamedSelector, name string, visited []string) (selectionExpression, error) {
+	for _, v := range visited {
+		if v == name {
+			return nil, fmt.Errorf("%w: cyclic selector reference %s", ErrInvalidSelector, strings.Join(append(visited, name), " -> "))
+		}
+	}
+
+	namedSelector, found := selectors[name]
+	if !found {
+		return nil, fmt.Errorf("%w: selector %q is not defined", ErrInvalidSelector, name)
+	}
+
+	return parseDefinition(selectors, namedSelector.Definition, append(visited, name))
+}
+
+// parseDefinition parses a selector definition as defined in https://docs.getdbt.com/reference/node-selection/yaml-selectors.
+// A definition is either a cli style string, a key-value pair (`tag: nightly`), a full method definition,
+// or a union or intersection of definitions.
+func parseDefinition(selectors map[string]NamedSelector, definition interface{}, visited []string) (selectionExpression, error) {
+	switch def := definition.(type) {
+	case string:
+		if strings.TrimSpace(def) == "" {
+			return nil, fmt.Errorf("%w: empty selector definition", ErrInvalidSelector)
+		}
+
+		return parseSelectorExpression(def)
+	case map[string]interface{}:
+		if values, found := def[definitionUnion]; found {
+			return parseDefinitionList(selectors, definitionUnion, values, visited)
+		}
+
+		if values, found := def[definitionIntersection]; found {
+			return parseDefinitionList(selectors, definitionIntersection, values, visited)
+		}
+
+		return parseMethodDefinition(selectors, def, visited)
+	default:
+		return nil, fmt.Errorf("%w: unsupported selector definition %v", ErrInvalidSelector, definition)
+	}
+}
+
+// parseDefinitionList parses a union or intersection. Exclude definitions within the list are removed from the result.
+func parseDefinitionList(selectors map[string]NamedSelector, listType string, values interface{}, visited []string) (selectionExpression, error) {
+	list, ok := values.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s should be a list", ErrInvalidSelector, listType)
+	}
+
+	var expressions, excludes []selectionExpression
+
+	for _, item := range list {
+		if itemMap, isMap := item.(map[string]interface{}); isMap && len(itemMap) == 1 {
+			if excludeDefinition, isExclude := itemMap[definitionExclude]; isExclude {
+				exclude, err := parseDefinitionList(selectors, definitionUnion, excludeDefinition, visited)
+				if err != nil {
+					return nil, err
+				}
+
+				excludes = append(excludes, exclude)
+
+				continue
+			}
+		}
+
+		expression, err := parseDefinition(selectors, item, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		expressions = append(expressions, expression)
+	}
+
+	var result selectionExpression
+
+	if listType == definitionIntersection {
+		result = intersectionExpression(expressions)
+	} else {
+		result = unionExpression(expressions)
+	}
+
+	if len(excludes) > 0 {
+		result = &differenceExpression{include: result, exclude: unionExpression(excludes)}
+	}
+
+	return result, nil
+}
+
+// parseMethodDefinition parses a key-value definition (`tag: nightly`) or a full method definition.
+func parseMethodDefinition(selectors map[string]NamedSelector, def map[string]interface{}, visited []string) (selectionExpression, error) {
+	if _, found := def[definitionMethod]; !found {
+		if len(def) != 1 {
+			return nil, fmt.Errorf("%w: unsupported selector definition %v", ErrInvalidSelector, def)
+		}
+
+		for key, value := range def {
+			def = map[string]interface{}{definitionMethod: key, definitionValue: value}
+		}
+	}
+
+	method, _ := def[definitionMethod].(string)
+	value, _ := def[definitionValue].(string)
+
+	var result selectionExpression
+
+	if method == definitionSelector {
+		expression, err := parseSelectorDefinition(selectors, value, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		result = expression
+	} else {
+		sel := &selector{
+			method:          method,
+			value:           value,
+			parents:         definitionBool(def, "parents"),
+			parentDepth:     definitionDepth(def, "parents_depth"),
+			children:        definitionBool(def, "children"),
+			childDepth:      definitionDepth(def, "children_depth"),
+			childrenParents: definitionBool(def, "childrens_parents"),
+		}
+
+		// a depth implies the graph operator, e.g. `2+model`
+		sel.parents = sel.parents || sel.parentDepth >= 0
+		sel.children = sel.children || sel.childDepth >= 0
+
+		err := sel.validate()
+		if err != nil {
+			return nil, err
+		}
+
+		result = sel
+	}
+
+	if excludeDefinition, found := def[definitionExclude]; found {
+		exclude, err := parseDefinitionList(selectors, definitionUnion, excludeDefinition, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		result = &differenceExpression{include: result, exclude: exclude}
+	}
+
+	return result, nil
+}
+
+func definitionBool(def map[string]interface{}, key string) bool {
+	value, _ := def[key].(bool)
+
+	return value
+}
+
+func definitionDepth(def map[string]interface{}, key string) int {
+	if value, ok := def[key].(float64); ok {
+		return int(value)
+	}
+
+	return -1
+}
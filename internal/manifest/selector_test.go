@@ -0,0 +1,193 @@
+package manifest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSelectorTestManifest() *Manifest {
+	table := "table"
+	view := "view"
+
+	return &Manifest{
+		Metadata: Metadata{ProjectName: "shop"},
+		Nodes: map[string]Node{
+			"seed.shop.raw_orders": {Name: "raw_orders", PackageName: "shop", ResourceType: "seed", OriginalFilePath: "seeds/raw_orders.csv", Fqn: []string{"shop", "raw_orders"}},
+			"model.shop.stg_orders": {Name: "stg_orders", PackageName: "shop", ResourceType: "model", OriginalFilePath: "models/staging/stg_orders.sql", Fqn: []string{"shop", "staging", "stg_orders"},
+				Tags: []string{"staging"}, Config: NodeConfig{Materialized: &view}, Access: "protected", DependsOn: NodeDependsOn{Nodes: []string{"seed.shop.raw_orders"}}},
+			"model.shop.orders": {Name: "orders", PackageName: "shop", ResourceType: "model", OriginalFilePath: "models/marts/orders.sql", Fqn: []string{"shop", "marts", "orders"},
+				Config: NodeConfig{Materialized: &table, Tags: []string{"finance"}}, Group: "finance", Access: "public", DependsOn: NodeDependsOn{Nodes: []string{"model.shop.stg_orders"}}},
+			"model.shop.revenue": {Name: "revenue", PackageName: "shop", ResourceType: "model", OriginalFilePath: "models/marts/revenue.sql", Fqn: []string{"shop", "marts", "revenue"},
+				Config: NodeConfig{Materialized: &table}, Group: "finance", Access: "protected", DependsOn: NodeDependsOn{Nodes: []string{"model.shop.orders", "model.utils.calendar"}}},
+			"model.utils.calendar": {Name: "calendar", PackageName: "utils", ResourceType: "model", OriginalFilePath: "models/calendar.sql", Fqn: []string{"utils", "calendar"},
+				Config: NodeConfig{Materialized: &table}},
+			"test.shop.not_null_orders_id": {Name: "not_null_orders_id", PackageName: "shop", ResourceType: "test", Tags: []string{"finance"}, DependsOn: NodeDependsOn{Nodes: []string{"model.shop.orders"}}},
+		},
+	}
+}
+
+func TestManifest_SelectDataObjectNodes(t *testing.T) {
+	m := newSelectorTestManifest()
+
+	tests := []struct {
+		name    string
+		selects string
+		exclude string
+		want    []string
+	}{
+		{name: "no selection", want: []string{"model.shop.orders", "model.shop.revenue", "model.shop.stg_orders", "model.utils.calendar", "seed.shop.raw_orders"}},
+		{name: "tag", selects: "tag:finance", want: []string{"model.shop.orders"}},
+		{name: "tag wildcard", selects: "tag:fin*", want: []string{"model.shop.orders"}},
+		{name: "path directory", selects: "path:models/marts", want: []string{"model.shop.orders", "model.shop.revenue"}},
+		{name: "path without method", selects: "models/staging/stg_orders.sql", want: []string{"model.shop.stg_orders"}},
+		{name: "package", selects: "package:utils", want: []string{"model.utils.calendar"}},
+		{name: "package this", selects: "package:this", want: []string{"model.shop.orders", "model.shop.revenue", "model.shop.stg_orders", "seed.shop.raw_orders"}},
+		{name: "model name", selects: "orders", want: []string{"model.shop.orders"}},
+		{name: "fqn prefix", selects: "shop.marts", want: []string{"model.shop.orders", "model.shop.revenue"}},
+		{name: "fqn wildcard", selects: "fqn:shop.*.stg_*", want: []string{"model.shop.stg_orders"}},
+		{name: "materialized", selects: "config.materialized:view", want: []string{"model.shop.stg_orders"}},
+		{name: "group", selects: "group:finance", want: []string{"model.shop.orders", "model.shop.revenue"}},
+		{name: "access", selects: "access:public", want: []string{"model.shop.orders"}},
+		{name: "union", selects: "access:public package:utils", want: []string{"model.shop.orders", "model.utils.calendar"}},
+		{name: "intersection", selects: "group:finance,access:protected", want: []string{"model.shop.revenue"}},
+		{name: "ancestors", selects: "+orders", want: []string{"model.shop.orders", "model.shop.stg_orders", "seed.shop.raw_orders"}},
+		{name: "ancestors with depth", selects: "1+orders", want: []string{"model.shop.orders", "model.shop.stg_orders"}},
+		{name: "descendants", selects: "stg_orders+", want: []string{"model.shop.orders", "model.shop.revenue", "model.shop.stg_orders"}},
+		{name: "descendants with depth", selects: "raw_orders+1", want: []string{"model.shop.stg_orders", "seed.shop.raw_orders"}},
+		{name: "descendants and their ancestors", selects: "@orders", want: []string{"model.shop.orders", "model.shop.revenue", "model.shop.stg_orders", "model.utils.calendar", "seed.shop.raw_orders"}},
+		{name: "exclude only", exclude: "package:utils path:seeds", want: []string{"model.shop.orders", "model.shop.revenue", "model.shop.stg_orders"}},
+		{name: "select and exclude", selects: "+revenue", exclude: "package:utils", want: []string{"model.shop.orders", "model.shop.revenue", "model.shop.stg_orders", "seed.shop.raw_orders"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection, err := ParseSelection(tt.selects, tt.exclude)
+			require.NoError(t, err)
+
+			nodes, err := m.SelectDataObjectNodes(selection)
+			require.NoError(t, err)
+
+			var got []string
+			for _, node := range nodes {
+				got = append(got, node.UniqueId)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseSelection_Invalid(t *testing.T) {
+	for _, expression := range []string{"unknown:value", "tag:", "@+orders", "orders++", "+"} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := ParseSelection(expression, "")
+			assert.ErrorIs(t, err, ErrInvalidSelector)
+
+			_, err = ParseSelection("", expression)
+			assert.ErrorIs(t, err, ErrInvalidSelector)
+		})
+	}
+}
+
+func TestSelection_String(t *testing.T) {
+	selection, err := ParseSelection(" tag:finance   marts.sales+ ", "orders_archive")
+	require.NoError(t, err)
+
+	assert.Equal(t, "select tag:finance marts.sales+; exclude orders_archive", selection.String())
+
+	selection, err = ParseSelection("", "")
+	require.NoError(t, err)
+
+	assert.Empty(t, selection.String())
+	assert.Equal(t, "selector finance", NamedSelection("finance").String())
+
+	var nilSelection *Selection
+	assert.Empty(t, nilSelection.String())
+}
+
+func TestManifest_Graph(t *testing.T) {
+	m := newSelectorTestManifest()
+
+	uniqueIds := func(nodes []*Node) []string {
+		result := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			result = append(result, node.UniqueId)
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []string{"model.shop.orders", "model.utils.calendar"}, uniqueIds(m.Parents("model.shop.revenue")))
+	assert.Equal(t, []string{"model.shop.revenue", "test.shop.not_null_orders_id"}, uniqueIds(m.Children("model.shop.orders")))
+	assert.Equal(t, []string{"model.shop.stg_orders", "seed.shop.raw_orders"}, uniqueIds(m.Ancestors("model.shop.orders", -1)))
+	assert.Equal(t, []string{"model.shop.stg_orders"}, uniqueIds(m.Descendants("seed.shop.raw_orders", 1)))
+	assert.Equal(t, []string{"model.shop.stg_orders", "model.shop.orders", "model.shop.revenue"}, uniqueIds(m.DescendantsWhere("seed.shop.raw_orders", -1, func(node *Node) bool {
+		return node.ResourceType == "model"
+	})))
+	assert.Equal(t, []string{"model.shop.stg_orders"}, uniqueIds(m.DescendantsWhere("seed.shop.raw_orders", -1, func(node *Node) bool {
+		return node.UniqueId != "model.shop.orders"
+	})))
+	assert.Empty(t, m.Parents("model.shop.unknown"))
+}
+
+func TestManifest_SelectDataObjectNodes_NamedSelector(t *testing.T) {
+	m := newSelectorTestManifest()
+
+	err := json.Unmarshal([]byte(`{
+		"marts": {"name": "marts", "definition": "path:models/marts"},
+		"finance": {"name": "finance", "definition": {"union": [
+			{"method": "group", "value": "finance", "parents": true, "parents_depth": 1},
+			{"exclude": [{"method": "fqn", "value": "revenue"}]}
+		]}},
+		"public_marts": {"name": "public_marts", "definition": {"intersection": [
+			{"method": "selector", "value": "marts"},
+			{"access": "public"}
+		]}},
+		"shop": {"name": "shop", "definition": {"method": "package", "value": "this", "exclude": [{"resource_type": "seed"}, "1+revenue"]}},
+		"cyclic": {"name": "cyclic", "definition": {"method": "selector", "value": "cyclic"}},
+		"invalid": {"name": "invalid", "definition": {"method": "state", "value": "modified"}},
+		"empty": {"name": "empty", "definition": ""},
+		"empty_in_union": {"name": "empty_in_union", "definition": {"union": ["path:models/marts", " "]}}
+	}`), &m.Selectors)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		selector string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "cli style definition", selector: "marts", want: []string{"model.shop.orders", "model.shop.revenue"}},
+		{name: "union with exclude", selector: "finance", want: []string{"model.shop.orders", "model.shop.stg_orders", "model.utils.calendar"}},
+		{name: "intersection with selector reference", selector: "public_marts", want: []string{"model.shop.orders"}},
+		{name: "method with exclude", selector: "shop", want: []string{"model.shop.stg_orders"}},
+		{name: "cyclic reference", selector: "cyclic", wantErr: true},
+		{name: "unsupported method", selector: "invalid", wantErr: true},
+		{name: "undefined selector", selector: "unknown", wantErr: true},
+		{name: "empty definition", selector: "empty", wantErr: true},
+		{name: "empty definition in union", selector: "empty_in_union", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := m.SelectDataObjectNodes(NamedSelection(tt.selector))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSelector)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var got []string
+			for _, node := range nodes {
+				got = append(got, node.UniqueId)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
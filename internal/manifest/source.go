@@ -0,0 +1,332 @@
+package manifest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	StdinLocation = "-"
+
+	DefaultLoadTimeout = 60 * time.Second
+
+	// DefaultMaxSize is the default limit on the size of a manifest, both as stored and after decompression.
+	DefaultMaxSize = int64(1 << 30)
+
+	fileScheme  = "file://"
+	httpScheme  = "http://"
+	httpsScheme = "https://"
+
+	checksumPrefix = "sha256:"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+var (
+	ErrChecksumMismatch = errors.New("manifest checksum mismatch")
+	ErrManifestTooLarge = errors.New("manifest too large")
+)
+
+type LoadOptions struct {
+	// BearerToken is sent in the Authorization header when the manifest is downloaded over http(s).
+	BearerToken string
+
+	// DbtCloudToken is sent in the Authorization header of the dbt Cloud API requests. It is never sent to other hosts.
+	DbtCloudToken string
+
+	// Timeout limits the time to download a remote manifest, including all dbt Cloud API requests.
+	Timeout time.Duration
+
+	// Checksum is the expected sha256 checksum (hex encoded, optionally prefixed with `sha256:`) of the manifest as stored in the location.
+	Checksum string
+
+	// MaxSize limits the size (in bytes) of a manifest downloaded over http(s) or from dbt Cloud, both as stored and after decompression. Defaults to DefaultMaxSize.
+	// A size of 0 or less disables the limit.
+	MaxSize int64
+
+	// DbtCloudBaseUrl is the base url of the dbt Cloud API. Defaults to DefaultDbtCloudBaseUrl.
+	DbtCloudBaseUrl string
+
+	HttpClient *http.Client
+	Stdin      io.Reader
+
+	// artifact is the name of the artifact downloaded from a dbt Cloud run.
+	artifact string
+}
+
+func WithBearerToken(token string) func(options *LoadOptions) {
+	return func(options *LoadOptions) {
+		options.BearerToken = token
+	}
+}
+
+func WithTimeout(timeout time.Duration) func(options *LoadOptions) {
+	return func(options *LoadOptions) {
+		options.Timeout = timeout
+	}
+}
+
+func WithChecksum(checksum string) func(options *LoadOptions) {
+	return func(options *LoadOptions) {
+		options.Checksum = checksum
+	}
+}
+
+func WithMaxSize(maxSize int64) func(options *LoadOptions) {
+	return func(options *LoadOptions) {
+		options.MaxSize = maxSize
+	}
+}
+
+func WithHttpClient(client *http.Client) func(options *LoadOptions) {
+	return func(options *LoadOptions) {
+		options.HttpClient = client
+	}
+}
+
+func WithStdin(reader io.Reader) func(options *LoadOptions) {
+	return func(options *LoadOptions) {
+		options.Stdin = reader
+	}
+}
+
+func newLoadOptions(ops ...func(options *LoadOptions)) *LoadOptions {
+	options := &LoadOptions{
+		Timeout:    DefaultLoadTimeout,
+		MaxSize:    DefaultMaxSize,
+		HttpClient: http.DefaultClient,
+		Stdin:      os.Stdin,
+		artifact:   dbtCloudManifestArtifact,
+	}
+
+	for _, op := range ops {
+		op(options)
+	}
+
+	return options
+}
+
+// source is a location a manifest can be read from.
+type source interface {
+	// key identifies the source within the parser cache.
+	key() string
+
+	// fetch reads the raw content of the manifest. If the cached entry is still valid, notModified is true and no content is returned.
+	// The returned version is used to validate the cache entry in a next fetch.
+	fetch(ctx context.Context, cached *cacheEntry) (content []byte, version string, notModified bool, err error)
+}
+
+func newSource(location string, options *LoadOptions) (source, error) {
+	switch {
+	case location == StdinLocation:
+		return &stdinSource{reader: options.Stdin}, nil
+	case strings.HasPrefix(location, httpScheme), strings.HasPrefix(location, httpsScheme):
+		return &httpSource{url: location, options: options}, nil
+	case strings.HasPrefix(location, DbtCloudScheme):
+		return newDbtCloudSource(location, options)
+	case strings.HasPrefix(location, fileScheme):
+		return newFileSource(strings.TrimPrefix(location, fileScheme))
+	default:
+		return newFileSource(location)
+	}
+}
+
+type fileSource struct {
+	path string
+}
+
+func newFileSource(path string) (*fileSource, error) {
+	filePath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("absoluting path: %w", err)
+	}
+
+	return &fileSource{path: filePath}, nil
+}
+
+func (s *fileSource) key() string {
+	return s.path
+}
+
+func (s *fileSource) fetch(_ context.Context, cached *cacheEntry) ([]byte, string, bool, error) {
+	fileInfo, err := os.Stat(s.path)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("stat dbt file: %w", err)
+	}
+
+	version := fileInfo.ModTime().UTC().Format(time.RFC3339Nano) + "/" + strconv.FormatInt(fileInfo.Size(), 10)
+
+	if cached != nil && cached.version == version {
+		return nil, version, true, nil
+	}
+
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("reading dbt file: %w", err)
+	}
+
+	return content, version, false, nil
+}
+
+type httpSource struct {
+	url     string
+	options *LoadOptions
+}
+
+func (s *httpSource) key() string {
+	return s.url
+}
+
+func (s *httpSource) fetch(ctx context.Context, cached *cacheEntry) ([]byte, string, bool, error) {
+	if s.options.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.options.Timeout)
+		defer cancel()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, http.NoBody)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("create request: %w", err)
+	}
+
+	if s.options.BearerToken != "" {
+		request.Header.Set("Authorization", "Bearer "+s.options.BearerToken)
+	}
+
+	if cached != nil && cached.version != "" {
+		request.Header.Set("If-None-Match", cached.version)
+	}
+
+	response, err := s.options.HttpClient.Do(request)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("download manifest: %w", err)
+	}
+
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotModified && cached != nil:
+		return nil, cached.version, true, nil
+	case response.StatusCode != http.StatusOK:
+		return nil, "", false, fmt.Errorf("download manifest: unexpected status %s", response.Status)
+	}
+
+	content, err := readLimited(response.Body, s.options.MaxSize)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("download manifest: %w", err)
+	}
+
+	return content, response.Header.Get("ETag"), false, nil
+}
+
+type stdinSource struct {
+	reader io.Reader
+}
+
+func (s *stdinSource) key() string {
+	return StdinLocation
+}
+
+// fetch reads the manifest from stdin. Stdin can only be read once, so a cached manifest is always reused.
+func (s *stdinSource) fetch(_ context.Context, cached *cacheEntry) ([]byte, string, bool, error) {
+	if cached != nil {
+		return nil, cached.version, true, nil
+	}
+
+	content, err := io.ReadAll(s.reader)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("reading manifest from stdin: %w", err)
+	}
+
+	return content, "", false, nil
+}
+
+func verifyChecksum(checksum [sha256.Size]byte, expected string) error {
+	if expected == "" {
+		return nil
+	}
+
+	expected = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(expected), checksumPrefix))
+
+	if actual := hex.EncodeToString(checksum[:]); actual != expected {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, expected, actual)
+	}
+
+	return nil
+}
+
+// decompress detects gzip and zstd compressed content based on the magic bytes. The decompressed content is limited to maxSize bytes.
+func decompress(content []byte, maxSize int64) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(content, gzipMagic):
+		reader, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("open gzip manifest: %w", err)
+		}
+
+		defer reader.Close()
+
+		result, err := readLimited(reader, maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("decompress gzip manifest: %w", err)
+		}
+
+		return result, nil
+	case bytes.HasPrefix(content, zstdMagic):
+		decoder, err := zstd.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("create zstd decoder: %w", err)
+		}
+
+		defer decoder.Close()
+
+		result, err := readLimited(decoder, maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("decompress zstd manifest: %w", err)
+		}
+
+		return result, nil
+	default:
+		if maxSize > 0 && int64(len(content)) > maxSize {
+			return nil, fmt.Errorf("%w: exceeds %d bytes", ErrManifestTooLarge, maxSize)
+		}
+
+		return content, nil
+	}
+}
+
+// readLimited reads all content of the reader, and fails when the content exceeds maxSize bytes. A maxSize of 0 or less disables the limit.
+func readLimited(reader io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize > 0 {
+		reader = io.LimitReader(reader, maxSize+1)
+	}
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+
+	if maxSize > 0 && int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrManifestTooLarge, maxSize)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,196 @@
+package manifest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipContent(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
+
+func zstdContent(t *testing.T, content string) []byte {
+	t.Helper()
+
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+
+	defer encoder.Close()
+
+	return encoder.EncodeAll([]byte(content), nil)
+}
+
+func sha256Hex(content []byte) string {
+	checksum := sha256.Sum256(content)
+
+	return hex.EncodeToString(checksum[:])
+}
+
+func TestParser_LoadManifest_Http(t *testing.T) {
+	content := gzipContent(t, testManifest)
+
+	var downloads atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/artifacts/manifest.json.gz":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			downloads.Add(1)
+
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write(content)
+		case "/artifacts/slow.json":
+			time.Sleep(200 * time.Millisecond)
+
+			_, _ = w.Write([]byte(testManifest))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p := NewManifestParser()
+
+	t.Run("download compressed manifest", func(t *testing.T) {
+		m, err := p.LoadManifest(ctx, server.URL+"/artifacts/manifest.json.gz", WithBearerToken("secret-token"), WithChecksum("sha256:"+sha256Hex(content)))
+		require.NoError(t, err)
+		assert.Equal(t, "project1", m.Metadata.ProjectName)
+
+		cached, err := p.LoadManifest(ctx, server.URL+"/artifacts/manifest.json.gz", WithBearerToken("secret-token"))
+		require.NoError(t, err)
+		assert.Same(t, m, cached)
+		assert.Equal(t, int32(1), downloads.Load(), "not modified manifest should not be downloaded again")
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		_, err := p.LoadManifest(ctx, server.URL+"/artifacts/manifest.json.gz", WithBearerToken("secret-token"), WithChecksum(strings.Repeat("0", 64)))
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("download exceeds max size", func(t *testing.T) {
+		_, err := NewManifestParser().LoadManifest(ctx, server.URL+"/artifacts/manifest.json.gz", WithBearerToken("secret-token"), WithMaxSize(int64(len(content))-1))
+		assert.ErrorIs(t, err, ErrManifestTooLarge)
+	})
+
+	t.Run("decompressed manifest exceeds max size", func(t *testing.T) {
+		require.Greater(t, len(testManifest), len(content))
+
+		_, err := NewManifestParser().LoadManifest(ctx, server.URL+"/artifacts/manifest.json.gz", WithBearerToken("secret-token"), WithMaxSize(int64(len(content))))
+		assert.ErrorIs(t, err, ErrManifestTooLarge)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		_, err := NewManifestParser().LoadManifest(ctx, server.URL+"/artifacts/manifest.json.gz")
+		assert.ErrorContains(t, err, "401")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := p.LoadManifest(ctx, server.URL+"/artifacts/unknown.json", WithBearerToken("secret-token"))
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := p.LoadManifest(ctx, server.URL+"/artifacts/slow.json", WithBearerToken("secret-token"), WithTimeout(20*time.Millisecond))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestParser_LoadManifest_Files(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	gzipPath := filepath.Join(dir, "manifest.json.gz")
+	require.NoError(t, os.WriteFile(gzipPath, gzipContent(t, testManifest), 0600))
+
+	zstdPath := filepath.Join(dir, "manifest.json.zst")
+	require.NoError(t, os.WriteFile(zstdPath, zstdContent(t, testManifest), 0600))
+
+	plainPath := filepath.Join(dir, "manifest.json")
+	require.NoError(t, os.WriteFile(plainPath, []byte(testManifest), 0600))
+
+	tests := []struct {
+		name     string
+		location string
+	}{
+		{name: "gzip", location: gzipPath},
+		{name: "zstd", location: zstdPath},
+		{name: "file url", location: "file://" + plainPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewManifestParser().LoadManifest(ctx, tt.location)
+			require.NoError(t, err)
+
+			assert.Equal(t, "project1", m.Metadata.ProjectName)
+			assert.Len(t, m.DataObjectNodes(), 3)
+		})
+	}
+}
+
+func TestParser_LoadManifest_Stdin(t *testing.T) {
+	ctx := context.Background()
+	p := NewManifestParser()
+
+	m, err := p.LoadManifest(ctx, StdinLocation, WithStdin(bytes.NewReader(gzipContent(t, testManifest))))
+	require.NoError(t, err)
+	assert.Equal(t, "project1", m.Metadata.ProjectName)
+
+	// Stdin can only be consumed once, the second syncer reuses the parsed manifest
+	cached, err := p.LoadManifest(ctx, StdinLocation, WithStdin(bytes.NewReader(nil)))
+	require.NoError(t, err)
+	assert.Same(t, m, cached)
+}
+
+func Test_decompress_MaxSize(t *testing.T) {
+	for name, content := range map[string][]byte{
+		"plain": []byte(testManifest),
+		"gzip":  gzipContent(t, testManifest),
+		"zstd":  zstdContent(t, testManifest),
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := decompress(content, int64(len(testManifest)))
+			require.NoError(t, err)
+			assert.Equal(t, testManifest, string(result))
+
+			_, err = decompress(content, int64(len(testManifest))-1)
+			assert.ErrorIs(t, err, ErrManifestTooLarge)
+		})
+	}
+}
@@ -1,8 +1,29 @@
 package manifest
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
 type Manifest struct {
-	Metadata Metadata        `json:"metadata"`
-	Nodes    map[string]Node `json:"nodes"`
+	Metadata  Metadata                 `json:"metadata"`
+	Nodes     map[string]Node          `json:"nodes"`
+	Sources   map[string]Node          `json:"sources"`
+	Exposures map[string]Exposure      `json:"exposures"`
+	Selectors map[string]NamedSelector `json:"selectors"`
+
+	indexOnce sync.Once
+	idx       *index
+}
+
+// NamedSelector is a selector defined in the selectors.yml file of the dbt project.
+type NamedSelector struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Default     bool        `json:"default"`
+	Definition  interface{} `json:"definition"`
 }
 
 type Metadata struct {
@@ -10,6 +31,7 @@ type Metadata struct {
 	DbtVersion       string `json:"dbt_version"`
 	ProjectName      string `json:"project_name"`
 	ProjectId        string `json:"project_id"`
+	AdapterType      string `json:"adapter_type"`
 }
 
 type Node struct {
@@ -36,6 +58,9 @@ type Node struct {
 	Language         string                 `json:"language"`
 	DependsOn        NodeDependsOn          `json:"depends_on"`
 	Access           string                 `json:"access"`
+	Group            string                 `json:"group"`
+	Version          ModelVersion           `json:"version"`
+	LatestVersion    ModelVersion           `json:"latest_version"`
 }
 
 type NodeConfig struct {
@@ -46,7 +71,16 @@ type NodeConfig struct {
 	Tags         []string               `json:"tags"`
 	Meta         map[string]interface{} `json:"meta"`
 	Group        *string                `json:"group"`
+	Access       *string                `json:"access"`
 	Materialized *string                `json:"materialized"`
+	Quoting      Quoting                `json:"quoting"`
+}
+
+// Quoting defines whether the database, schema and identifier of a relation are quoted. Undefined values use the default of the adapter.
+type Quoting struct {
+	Database   *bool `json:"database"`
+	Schema     *bool `json:"schema"`
+	Identifier *bool `json:"identifier"`
 }
 
 type Column struct {
@@ -56,6 +90,27 @@ type Column struct {
 	DataType    *string    `json:"data_type"`
 	Tags        []string   `json:"tags"`
 	Config      NodeConfig `json:"config"`
+	Quote       *bool      `json:"quote"`
+}
+
+// Exposure is a downstream use of the dbt project, e.g. a dashboard or ML application, defined in the exposures section of the manifest.
+type Exposure struct {
+	Name        string        `json:"name"`
+	Label       string        `json:"label"`
+	Type        string        `json:"type"`
+	PackageName string        `json:"package_name"`
+	UniqueId    string        `json:"unique_id"`
+	Url         string        `json:"url"`
+	Owner       ExposureOwner `json:"owner"`
+	DependsOn   NodeDependsOn `json:"depends_on"`
+	Config      NodeConfig    `json:"config"`
+	Tags        []string      `json:"tags"`
+	Meta        Meta          `json:"meta"`
+}
+
+type ExposureOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
 }
 
 type NodeDependsOn struct {
@@ -65,31 +120,136 @@ type NodeDependsOn struct {
 
 type Meta struct {
 	Raito RaitoMeta `json:"raito"`
+
+	// Values contains all values of the meta, including the raito meta.
+	Values map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the raito meta and keeps all values of the meta, e.g. to match rules on the meta of columns.
+func (m *Meta) UnmarshalJSON(data []byte) error {
+	type raitoOnly Meta
+
+	var meta raitoOnly
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("parsing meta: %w", err)
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("parsing meta: %w", err)
+	}
+
+	*m = Meta(meta)
+	m.Values = values
+
+	return nil
+}
+
+// MergedMeta returns the values of the meta, overwritten by the values of the config meta.
+// dbt stores the meta of columns in the meta itself, and the meta of nodes (also) in the config meta.
+func MergedMeta(meta *Meta, configMeta map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(meta.Values)+len(configMeta))
+
+	for key, value := range meta.Values {
+		result[key] = value
+	}
+
+	for key, value := range configMeta {
+		result[key] = value
+	}
+
+	return result
 }
 
 type RaitoMeta struct {
 	Grant  []Grant  `json:"grant,omitempty"`
 	Filter []Filter `json:"filter,omitempty"`
 	Mask   *Mask    `json:"mask,omitempty"`
+
+	// StopPropagation excludes the node, and the nodes downstream of it, from grants propagated from upstream nodes.
+	StopPropagation bool `json:"stop_propagation,omitempty"`
+}
+
+func (r *RaitoMeta) IsEmpty() bool {
+	return len(r.Grant) == 0 && len(r.Filter) == 0 && r.Mask == nil
 }
 
 type Grant struct {
 	Name              string   `json:"name"`
+	Template          string   `json:"template,omitempty"`
 	Permissions       []string `json:"permissions"`
 	GlobalPermissions []string `json:"global_permissions"`
 	Owners            []string `json:"owners,omitempty"`
 	Category          *string  `json:"category,omitempty"`
 	Type              *string  `json:"type,omitempty"`
+	Refs              []string `json:"refs,omitempty"`
+	Scope             string   `json:"scope,omitempty"`
+	WhatRule          string   `json:"what_rule,omitempty"`
+	WhatDoTypes       []string `json:"what_do_types,omitempty"`
+	WhoRule           string   `json:"who_rule,omitempty"`
+	Propagate         string   `json:"propagate,omitempty"`
+	PropagateDepth    int      `json:"propagate_depth,omitempty"`
 }
 
+const (
+	// GrantScopeSchema grants the permissions on the schema of the node instead of the node itself.
+	GrantScopeSchema = "schema"
+	// GrantScopeDatabase grants the permissions on the database of the node instead of the node itself.
+	GrantScopeDatabase = "database"
+
+	// GrantPropagateDownstream adds all models downstream of the node to the grant.
+	GrantPropagateDownstream = "downstream"
+)
+
 type Filter struct {
 	Name       string   `json:"name"`
+	Template   string   `json:"template,omitempty"`
 	PolicyRule string   `json:"policy_rule"`
+	Criteria   string   `json:"criteria,omitempty"`
 	Owners     []string `json:"owners,omitempty"`
+	WhoRule    string   `json:"who_rule,omitempty"`
 }
 
 type Mask struct {
-	Name   string   `json:"name"`
-	Type   *string  `json:"type,omitempty"`
-	Owners []string `json:"owners,omitempty"`
+	Name      string   `json:"name"`
+	Type      *string  `json:"type,omitempty"`
+	Owners    []string `json:"owners,omitempty"`
+	WhoRule   string   `json:"who_rule,omitempty"`
+	Propagate bool     `json:"propagate,omitempty"`
+
+	// PropagatedFrom is the column (`<unique id>.<column>`) a propagated mask is propagated from. It is empty for declared masks.
+	PropagatedFrom string `json:"-"`
+}
+
+// IsLatestVersion returns true if the node is not versioned or is the latest version of a versioned model.
+func (n *Node) IsLatestVersion() bool {
+	return n.Version == "" || n.LatestVersion == "" || n.Version == n.LatestVersion
+}
+
+// ModelVersion is the version identifier of a versioned model.
+// dbt allows both numeric and string versions, both are stored as string.
+type ModelVersion string
+
+func (v *ModelVersion) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*v = ""
+
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*v = ModelVersion(str)
+
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("parsing model version %s: %w", string(data), err)
+	}
+
+	*v = ModelVersion(number.String())
+
+	return nil
 }
@@ -0,0 +1,227 @@
+package manifest
+
+import (
+	"crypto/md5" //nolint:gosec // dbt uses md5 to generate project ids
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	MinSupportedSchemaVersion = 4
+	MaxSupportedSchemaVersion = 12
+
+	defaultModelAccess = "protected"
+	raitoMetaKey       = "raito"
+)
+
+var ErrUnsupportedSchemaVersion = errors.New("unsupported dbt manifest schema version")
+
+var schemaVersionRegex = regexp.MustCompile(`/manifest/v(\d+)\.json$`)
+
+// schemaAdapter normalises a manifest of a specific schema version into the internal Manifest model.
+type schemaAdapter func(m *Manifest) error
+
+// schemaAdapters lists for each supported schema version the adapters that should be applied after decoding.
+//
+//   - v4 - v9 (dbt 1.0 - 1.5): the metadata only contains the project id, not the project name.
+//   - v4 - v8 (dbt 1.0 - 1.4): no groups, access or model versions.
+//   - v9 - v11 (dbt 1.5 - 1.7): groups, access and model versions are defined on the node, and optionally in the config.
+//   - v12 (dbt 1.8+): meta of nodes and columns is moving into the config block.
+var schemaAdapters = map[int][]schemaAdapter{
+	4:  {adaptProjectName, adaptConfigMeta, adaptDefaultAccess},
+	5:  {adaptProjectName, adaptConfigMeta, adaptDefaultAccess},
+	6:  {adaptProjectName, adaptConfigMeta, adaptDefaultAccess},
+	7:  {adaptProjectName, adaptConfigMeta, adaptDefaultAccess},
+	8:  {adaptProjectName, adaptConfigMeta, adaptDefaultAccess},
+	9:  {adaptProjectName, adaptConfigMeta, adaptConfigGroupAndAccess, adaptDefaultAccess},
+	10: {adaptConfigMeta, adaptConfigGroupAndAccess, adaptDefaultAccess},
+	11: {adaptConfigMeta, adaptConfigGroupAndAccess, adaptDefaultAccess},
+	12: {adaptConfigMeta, adaptColumnConfigMeta, adaptConfigGroupAndAccess, adaptDefaultAccess},
+}
+
+// SchemaVersion returns the manifest schema version number defined in the metadata.
+func (m *Metadata) SchemaVersion() (int, error) {
+	matches := schemaVersionRegex.FindStringSubmatch(m.DbtSchemaVersion)
+	if matches == nil {
+		return 0, fmt.Errorf("%w: unable to detect version from %q", ErrUnsupportedSchemaVersion, m.DbtSchemaVersion)
+	}
+
+	version, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q: %w", ErrUnsupportedSchemaVersion, m.DbtSchemaVersion, err)
+	}
+
+	return version, nil
+}
+
+func detectSchemaVersion(jsonBytes []byte) (int, error) {
+	var header struct {
+		Metadata Metadata `json:"metadata"`
+	}
+
+	err := json.Unmarshal(jsonBytes, &header)
+	if err != nil {
+		return 0, fmt.Errorf("parsing dbt file metadata: %w", err)
+	}
+
+	version, err := header.Metadata.SchemaVersion()
+	if err != nil {
+		return 0, err
+	}
+
+	if version < MinSupportedSchemaVersion || version > MaxSupportedSchemaVersion {
+		return 0, fmt.Errorf("%w: v%d (dbt %s), supported versions are v%d - v%d", ErrUnsupportedSchemaVersion, version, header.Metadata.DbtVersion, MinSupportedSchemaVersion, MaxSupportedSchemaVersion)
+	}
+
+	return version, nil
+}
+
+func adaptManifest(m *Manifest, version int) error {
+	for _, adapter := range schemaAdapters[version] {
+		err := adapter(m)
+		if err != nil {
+			return fmt.Errorf("adapting manifest v%d: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// adaptProjectName resolves the project name of older manifests.
+// dbt generates the project id as the md5 hash of the project name, so the root package can be found within the nodes.
+func adaptProjectName(m *Manifest) error {
+	if m.Metadata.ProjectName != "" || m.Metadata.ProjectId == "" {
+		return nil
+	}
+
+	for _, node := range m.Nodes {
+		hash := md5.Sum([]byte(node.PackageName)) //nolint:gosec // dbt uses md5 to generate project ids
+		if hex.EncodeToString(hash[:]) == m.Metadata.ProjectId {
+			m.Metadata.ProjectName = node.PackageName
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unable to resolve project name of project %s", m.Metadata.ProjectId)
+}
+
+// adaptConfigMeta uses the raito meta defined in the node (or exposure) config if no raito meta is defined on the node itself.
+func adaptConfigMeta(m *Manifest) error {
+	for uniqueId, node := range m.Nodes {
+		if !node.Meta.Raito.IsEmpty() {
+			continue
+		}
+
+		raitoMeta, err := decodeRaitoMeta(node.Config.Meta)
+		if err != nil {
+			return fmt.Errorf("node %s: %w", uniqueId, err)
+		}
+
+		if raitoMeta == nil {
+			continue
+		}
+
+		node.Meta.Raito = *raitoMeta
+		m.Nodes[uniqueId] = node
+	}
+
+	for uniqueId, exposure := range m.Exposures {
+		if !exposure.Meta.Raito.IsEmpty() {
+			continue
+		}
+
+		raitoMeta, err := decodeRaitoMeta(exposure.Config.Meta)
+		if err != nil {
+			return fmt.Errorf("exposure %s: %w", uniqueId, err)
+		}
+
+		if raitoMeta == nil {
+			continue
+		}
+
+		exposure.Meta.Raito = *raitoMeta
+		m.Exposures[uniqueId] = exposure
+	}
+
+	return nil
+}
+
+// adaptColumnConfigMeta uses the raito meta defined in the column config if no raito meta is defined on the column itself.
+func adaptColumnConfigMeta(m *Manifest) error {
+	for uniqueId, node := range m.Nodes {
+		for columnName, column := range node.Columns {
+			if !column.Meta.Raito.IsEmpty() {
+				continue
+			}
+
+			raitoMeta, err := decodeRaitoMeta(column.Config.Meta)
+			if err != nil {
+				return fmt.Errorf("column %s of node %s: %w", columnName, uniqueId, err)
+			}
+
+			if raitoMeta == nil {
+				continue
+			}
+
+			column.Meta.Raito = *raitoMeta
+			node.Columns[columnName] = column
+		}
+	}
+
+	return nil
+}
+
+// adaptConfigGroupAndAccess uses the group and access defined in the node config if they are not defined on the node itself.
+func adaptConfigGroupAndAccess(m *Manifest) error {
+	for uniqueId, node := range m.Nodes {
+		if node.Group == "" && node.Config.Group != nil {
+			node.Group = *node.Config.Group
+		}
+
+		if node.Access == "" && node.Config.Access != nil {
+			node.Access = *node.Config.Access
+		}
+
+		m.Nodes[uniqueId] = node
+	}
+
+	return nil
+}
+
+// adaptDefaultAccess sets the default dbt access level on models without access definition.
+func adaptDefaultAccess(m *Manifest) error {
+	for uniqueId, node := range m.Nodes {
+		if node.ResourceType == "model" && node.Access == "" {
+			node.Access = defaultModelAccess
+			m.Nodes[uniqueId] = node
+		}
+	}
+
+	return nil
+}
+
+func decodeRaitoMeta(meta map[string]interface{}) (*RaitoMeta, error) {
+	raw, found := meta[raitoMetaKey]
+	if !found || raw == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config meta: %w", err)
+	}
+
+	var result RaitoMeta
+
+	err = json.Unmarshal(jsonBytes, &result)
+	if err != nil {
+		return nil, fmt.Errorf("decoding raito config meta: %w", err)
+	}
+
+	return &result, nil
+}
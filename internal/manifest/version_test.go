@@ -0,0 +1,164 @@
+package manifest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_LoadManifest_SchemaVersions(t *testing.T) {
+	type node struct {
+		access     string
+		group      string
+		version    ModelVersion
+		latest     ModelVersion
+		grants     []string
+		maskColumn string
+		mask       string
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		wantProject string
+		wantAdapter string
+		wantNodes   map[string]node
+	}{
+		{
+			name:        "v4 (dbt 1.0)",
+			path:        "testdata/manifest_v4.json",
+			wantProject: "jaffle_shop",
+			wantAdapter: "postgres",
+			wantNodes: map[string]node{
+				"model.jaffle_shop.orders":     {access: "protected", grants: []string{"finance_read"}, maskColumn: "email", mask: "email_mask"},
+				"model.jaffle_shop.stg_orders": {access: "protected"},
+			},
+		},
+		{
+			name:        "v7 (dbt 1.3)",
+			path:        "testdata/manifest_v7.json",
+			wantProject: "jaffle_shop",
+			wantAdapter: "snowflake",
+			wantNodes: map[string]node{
+				"model.jaffle_shop.orders":   {access: "protected", grants: []string{"finance_read"}},
+				"seed.jaffle_shop.customers": {},
+			},
+		},
+		{
+			name:        "v9 (dbt 1.5)",
+			path:        "testdata/manifest_v9.json",
+			wantProject: "jaffle_shop",
+			wantAdapter: "databricks",
+			wantNodes: map[string]node{
+				"model.jaffle_shop.orders.v1":    {access: "public", group: "finance", version: "1", latest: "2"},
+				"model.jaffle_shop.orders.v2":    {access: "public", group: "finance", version: "2", latest: "2", grants: []string{"finance_read"}},
+				"model.jaffle_shop.stg_payments": {access: "protected"},
+			},
+		},
+		{
+			name:        "v12 (dbt 1.10)",
+			path:        "testdata/manifest_v12.json",
+			wantProject: "jaffle_shop",
+			wantAdapter: "bigquery",
+			wantNodes: map[string]node{
+				"model.jaffle_shop.customers": {access: "protected", group: "marketing", grants: []string{"marketing_read"}, maskColumn: "email", mask: "email_mask"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewManifestParser().LoadManifest(context.Background(), tt.path)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantProject, m.Metadata.ProjectName)
+			assert.Equal(t, tt.wantAdapter, m.Metadata.AdapterType)
+			assert.Len(t, m.Nodes, len(tt.wantNodes))
+
+			for uniqueId, want := range tt.wantNodes {
+				got, found := m.Nodes[uniqueId]
+				require.Truef(t, found, "node %s not found", uniqueId)
+
+				assert.Equalf(t, want.access, got.Access, "access of %s", uniqueId)
+				assert.Equalf(t, want.group, got.Group, "group of %s", uniqueId)
+				assert.Equalf(t, want.version, got.Version, "version of %s", uniqueId)
+				assert.Equalf(t, want.latest, got.LatestVersion, "latest version of %s", uniqueId)
+
+				var grants []string
+				for _, grant := range got.Meta.Raito.Grant {
+					grants = append(grants, grant.Name)
+				}
+
+				assert.Equalf(t, want.grants, grants, "grants of %s", uniqueId)
+
+				if want.maskColumn != "" {
+					require.NotNilf(t, got.Columns[want.maskColumn].Meta.Raito.Mask, "mask of %s.%s", uniqueId, want.maskColumn)
+					assert.Equal(t, want.mask, got.Columns[want.maskColumn].Meta.Raito.Mask.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestParser_LoadManifest_Exposures(t *testing.T) {
+	m, err := NewManifestParser().LoadManifest(context.Background(), "testdata/manifest_v12.json")
+	require.NoError(t, err)
+
+	exposures := m.OrderedExposures()
+	require.Len(t, exposures, 1)
+
+	exposure := exposures[0]
+	assert.Equal(t, "exposure.jaffle_shop.customer_dashboard", exposure.UniqueId)
+	assert.Equal(t, "dashboard", exposure.Type)
+	assert.Equal(t, ExposureOwner{Name: "Analytics", Email: "analytics@jaffleshop.com"}, exposure.Owner)
+	assert.Equal(t, []string{"model.jaffle_shop.customers"}, exposure.DependsOn.Nodes)
+	assert.Equal(t, []Grant{{Name: "customer_dashboard_read", Permissions: []string{"SELECT"}}}, exposure.Meta.Raito.Grant)
+}
+
+func TestParser_LoadManifest_UnsupportedSchemaVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "v3 (dbt 0.21)", path: "testdata/manifest_v3.json"},
+		{name: "v13", path: "testdata/manifest_v13.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewManifestParser().LoadManifest(context.Background(), tt.path)
+			assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+		})
+	}
+}
+
+func TestMetadata_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		schemaVersion string
+		want          int
+		wantErr       bool
+	}{
+		{schemaVersion: "https://schemas.getdbt.com/dbt/manifest/v4.json", want: 4},
+		{schemaVersion: "https://schemas.getdbt.com/dbt/manifest/v12.json", want: 12},
+		{schemaVersion: "https://schemas.getdbt.com/dbt/catalog/v1.json", wantErr: true},
+		{schemaVersion: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schemaVersion, func(t *testing.T) {
+			m := Metadata{DbtSchemaVersion: tt.schemaVersion}
+
+			got, err := m.SchemaVersion()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+type Casing string
+
+const (
+	// CasingAdapter normalises identifiers as the adapter of the manifest stores them.
+	CasingAdapter  Casing = ""
+	CasingUpper    Casing = "upper"
+	CasingLower    Casing = "lower"
+	CasingPreserve Casing = "preserve"
+)
+
+const (
+	AdapterSnowflake  = "snowflake"
+	AdapterBigQuery   = "bigquery"
+	AdapterRedshift   = "redshift"
+	AdapterPostgres   = "postgres"
+	AdapterDatabricks = "databricks"
+	AdapterSpark      = "spark"
+)
+
+var ErrInvalidCasing = errors.New("invalid identifier casing")
+
+// scopePlaceholder replaces the fields that are not part of a schema or database fullname when rendering the template.
+const scopePlaceholder = "\x00"
+
+// identifierPart is the part of a fullname an identifier is used for. The default quoting of dbt depends on it.
+type identifierPart int
+
+const (
+	partDatabase identifierPart = iota
+	partSchema
+	partIdentifier
+	partColumn
+)
+
+// ParseCasing parses the identifier casing override. An empty value uses the casing of the adapter.
+func ParseCasing(value string) (Casing, error) {
+	switch casing := Casing(strings.ToLower(strings.TrimSpace(value))); casing {
+	case CasingAdapter, CasingUpper, CasingLower, CasingPreserve:
+		return casing, nil
+	default:
+		return "", fmt.Errorf("%w %q: expected %s, %s or %s", ErrInvalidCasing, value, CasingUpper, CasingLower, CasingPreserve)
+	}
+}
+
+// Namer builds the Raito fullnames of the data objects and columns defined in a manifest.
+type Namer struct {
+	prefix          string
+	casing          Casing
+	template        *template.Template
+	databaseMapping map[string]string
+	schemaMapping   map[string]string
+}
+
+func NewNamer(prefix string, casing Casing, options ...func(namer *Namer)) *Namer {
+	result := &Namer{
+		prefix: prefix,
+		casing: casing,
+	}
+
+	for _, option := range options {
+		option(result)
+	}
+
+	return result
+}
+
+// WithTemplate builds the fullnames with a template created by ParseTemplate instead of prepending the prefix.
+func WithTemplate(tmpl *template.Template) func(namer *Namer) {
+	return func(namer *Namer) {
+		namer.template = tmpl
+	}
+}
+
+// WithDatabaseMapping renames the databases defined in the manifest, e.g. to map the database of a development target on the production database.
+func WithDatabaseMapping(mapping map[string]string) func(namer *Namer) {
+	return func(namer *Namer) {
+		namer.databaseMapping = mapping
+	}
+}
+
+// WithSchemaMapping renames the schemas defined in the manifest.
+func WithSchemaMapping(mapping map[string]string) func(namer *Namer) {
+	return func(namer *Namer) {
+		namer.schemaMapping = mapping
+	}
+}
+
+// DataObject returns the fullname of the data object of the node. Without template, the fullname is the prefixed relation name.
+func (n *Namer) DataObject(m *manifest.Manifest, node *manifest.Node) (string, error) {
+	data := n.templateData(m, node)
+
+	if n.template == nil {
+		return n.prefix + strings.Join([]string{data.Database, data.Schema, data.Alias}, "."), nil
+	}
+
+	return executeTemplate(n.template, data)
+}
+
+// Schema returns the fullname of the schema of the node.
+// With a template, the fullname is cut at the separator before the first segment that uses Alias or Name.
+func (n *Namer) Schema(m *manifest.Manifest, node *manifest.Node) (string, error) {
+	data := n.templateData(m, node)
+
+	if n.template == nil {
+		return n.prefix + data.Database + "." + data.Schema, nil
+	}
+
+	data.Alias, data.Name = scopePlaceholder, scopePlaceholder
+
+	return n.executeScopeTemplate("schema", data)
+}
+
+// Database returns the fullname of the database of the node.
+// With a template, the fullname is cut at the separator before the first segment that uses Schema, Alias or Name.
+func (n *Namer) Database(m *manifest.Manifest, node *manifest.Node) (string, error) {
+	data := n.templateData(m, node)
+
+	if n.template == nil {
+		return n.prefix + data.Database, nil
+	}
+
+	data.Schema, data.Alias, data.Name = scopePlaceholder, scopePlaceholder, scopePlaceholder
+
+	return n.executeScopeTemplate("database", data)
+}
+
+// executeScopeTemplate renders the template with a placeholder for the fields that are not part of the scope,
+// and returns the segments before the first segment that contains the placeholder.
+func (n *Namer) executeScopeTemplate(scope string, data *TemplateData) (string, error) {
+	result, err := executeTemplate(n.template, data)
+	if err != nil {
+		return "", err
+	}
+
+	idx := strings.Index(result, scopePlaceholder)
+	if idx < 0 {
+		return "", fmt.Errorf("%s name: the fullname template does not use .Alias or .Name", scope)
+	}
+
+	end := strings.LastIndex(result[:idx], ".")
+	if end <= 0 {
+		return "", fmt.Errorf("%s name: the fullname template has no %s segment", scope, scope)
+	}
+
+	return result[:end], nil
+}
+
+// Column returns the fullname of a column of the node.
+// If the template does not use the column, the column name is appended to the fullname of the data object.
+func (n *Namer) Column(m *manifest.Manifest, node *manifest.Node, column *manifest.Column) (string, error) {
+	doName, err := n.DataObject(m, node)
+	if err != nil {
+		return "", err
+	}
+
+	columnName := n.ColumnName(m, column)
+
+	if n.template == nil {
+		return doName + "." + columnName, nil
+	}
+
+	data := n.templateData(m, node)
+	data.Column = columnName
+
+	result, err := executeTemplate(n.template, data)
+	if err != nil {
+		return "", err
+	}
+
+	if result == doName {
+		return doName + "." + columnName, nil
+	}
+
+	return result, nil
+}
+
+// ColumnName returns the name of the column as stored in the data source, using the same casing as Column.
+func (n *Namer) ColumnName(m *manifest.Manifest, column *manifest.Column) string {
+	return n.normalise(m, partColumn, column.Name, column.Quote)
+}
+
+func (n *Namer) templateData(m *manifest.Manifest, node *manifest.Node) *TemplateData {
+	quoting := node.Config.Quoting
+
+	database := node.Database
+	if mapped, found := n.databaseMapping[database]; found {
+		database = mapped
+	}
+
+	schema := node.Schema
+	if mapped, found := n.schemaMapping[schema]; found {
+		schema = mapped
+	}
+
+	alias := node.Alias
+	if alias == "" {
+		alias = node.Name
+	}
+
+	return &TemplateData{
+		Prefix:   n.prefix,
+		Database: n.normalise(m, partDatabase, database, quoting.Database),
+		Schema:   n.normalise(m, partSchema, schema, quoting.Schema),
+		Alias:    n.normalise(m, partIdentifier, alias, quoting.Identifier),
+		Name:     n.normalise(m, partIdentifier, node.Name, quoting.Identifier),
+		Adapter:  m.Metadata.AdapterType,
+		Project:  m.Metadata.ProjectName,
+	}
+}
+
+func (n *Namer) normalise(m *manifest.Manifest, part identifierPart, name string, quoted *bool) string {
+	switch n.casing {
+	case CasingUpper:
+		return strings.ToUpper(name)
+	case CasingLower:
+		return strings.ToLower(name)
+	case CasingPreserve:
+		return name
+	case CasingAdapter:
+	}
+
+	isQuoted := defaultQuoting(m.Metadata.AdapterType, part)
+	if quoted != nil {
+		isQuoted = *quoted
+	}
+
+	switch strings.ToLower(m.Metadata.AdapterType) {
+	case AdapterSnowflake:
+		// Snowflake stores unquoted identifiers in upper case
+		if !isQuoted {
+			return strings.ToUpper(name)
+		}
+	case AdapterRedshift, AdapterPostgres:
+		// Postgres and Redshift store unquoted identifiers in lower case
+		if !isQuoted {
+			return strings.ToLower(name)
+		}
+	case AdapterDatabricks, AdapterSpark:
+		// Databricks identifiers are case-insensitive and stored in lower case, even when quoted
+		return strings.ToLower(name)
+	case AdapterBigQuery:
+		// BigQuery preserves the case of identifiers
+	}
+
+	return name
+}
+
+// defaultQuoting returns whether dbt quotes the identifier if no quoting is configured.
+// Postgres and Redshift quote relation identifiers by default, columns are never quoted by default.
+func defaultQuoting(adapterType string, part identifierPart) bool {
+	if part == partColumn {
+		return false
+	}
+
+	switch strings.ToLower(adapterType) {
+	case AdapterRedshift, AdapterPostgres:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,67 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/raito-io/bexpression/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+func TestNamer(t *testing.T) {
+	node := &manifest.Node{Database: "Analytics", Schema: "finance", Name: "Orders"}
+	quotedNode := &manifest.Node{Database: "Analytics", Schema: "finance", Name: "Orders", Config: manifest.NodeConfig{Quoting: manifest.Quoting{Identifier: utils.Ptr(true)}}}
+	unquotedNode := &manifest.Node{Database: "Analytics", Schema: "Finance", Name: "Orders", Config: manifest.NodeConfig{Quoting: manifest.Quoting{Schema: utils.Ptr(false)}}}
+
+	column := &manifest.Column{Name: "Email"}
+	quotedColumn := &manifest.Column{Name: "Email", Quote: utils.Ptr(true)}
+
+	tests := []struct {
+		name       string
+		adapter    string
+		casing     Casing
+		node       *manifest.Node
+		column     *manifest.Column
+		wantDo     string
+		wantColumn string
+	}{
+		{name: "snowflake", adapter: "snowflake", node: node, column: column, wantDo: "ANALYTICS.FINANCE.ORDERS", wantColumn: "ANALYTICS.FINANCE.ORDERS.EMAIL"},
+		{name: "snowflake quoted", adapter: "snowflake", node: quotedNode, column: quotedColumn, wantDo: "ANALYTICS.FINANCE.Orders", wantColumn: "ANALYTICS.FINANCE.Orders.Email"},
+		{name: "bigquery", adapter: "bigquery", node: node, column: column, wantDo: "Analytics.finance.Orders", wantColumn: "Analytics.finance.Orders.Email"},
+		{name: "postgres", adapter: "postgres", node: node, column: column, wantDo: "Analytics.finance.Orders", wantColumn: "Analytics.finance.Orders.email"},
+		{name: "redshift unquoted", adapter: "redshift", node: unquotedNode, column: quotedColumn, wantDo: "Analytics.finance.Orders", wantColumn: "Analytics.finance.Orders.Email"},
+		{name: "databricks", adapter: "databricks", node: quotedNode, column: quotedColumn, wantDo: "analytics.finance.orders", wantColumn: "analytics.finance.orders.email"},
+		{name: "unknown adapter", adapter: "duckdb", node: node, column: column, wantDo: "Analytics.finance.Orders", wantColumn: "Analytics.finance.Orders.Email"},
+		{name: "override lower", adapter: "snowflake", casing: CasingLower, node: node, column: column, wantDo: "analytics.finance.orders", wantColumn: "analytics.finance.orders.email"},
+		{name: "override upper", adapter: "bigquery", casing: CasingUpper, node: node, column: column, wantDo: "ANALYTICS.FINANCE.ORDERS", wantColumn: "ANALYTICS.FINANCE.ORDERS.EMAIL"},
+		{name: "override preserve", adapter: "snowflake", casing: CasingPreserve, node: node, column: column, wantDo: "Analytics.finance.Orders", wantColumn: "Analytics.finance.Orders.Email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &manifest.Manifest{Metadata: manifest.Metadata{AdapterType: tt.adapter}}
+			namer := NewNamer("prefix.", tt.casing)
+
+			doName, err := namer.DataObject(m, tt.node)
+			require.NoError(t, err)
+			assert.Equal(t, "prefix."+tt.wantDo, doName)
+
+			columnName, err := namer.Column(m, tt.node, tt.column)
+			require.NoError(t, err)
+			assert.Equal(t, "prefix."+tt.wantColumn, columnName)
+		})
+	}
+}
+
+func TestParseCasing(t *testing.T) {
+	for value, want := range map[string]Casing{"": CasingAdapter, "upper": CasingUpper, "Lower": CasingLower, " preserve ": CasingPreserve} {
+		got, err := ParseCasing(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseCasing("camel")
+	assert.ErrorIs(t, err, ErrInvalidCasing)
+}
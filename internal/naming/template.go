@@ -0,0 +1,123 @@
+package naming
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the data available in the fullname template. All names are already renamed and normalised.
+type TemplateData struct {
+	// Prefix is the configured data object prefix, including the trailing dot.
+	Prefix   string
+	Database string
+	Schema   string
+	// Alias is the identifier of the relation in the data warehouse.
+	Alias string
+	// Name is the name of the dbt node.
+	Name string
+	// Column is empty when the fullname of a data object is built.
+	Column  string
+	Adapter string
+	Project string
+}
+
+// templateFuncs take the input string as last argument, so they can be used in pipelines, e.g. `{{.Schema | trimPrefix "dbt_"}}`.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"replace": func(old string, replacement string, s string) string {
+		return strings.ReplaceAll(s, old, replacement)
+	},
+	"trimPrefix": func(prefix string, s string) string {
+		return strings.TrimPrefix(s, prefix)
+	},
+	"trimSuffix": func(suffix string, s string) string {
+		return strings.TrimSuffix(s, suffix)
+	},
+}
+
+// ParseTemplate parses a Go text/template used to build the data object fullnames,
+// e.g. `{{.Database}}.{{.Schema}}.{{.Alias}}{{with .Column}}.{{.}}{{end}}`.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("fullname").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse fullname template: %w", err)
+	}
+
+	// Execute the template once to detect references to undefined fields
+	_, err = executeTemplate(tmpl, &TemplateData{Database: "database", Schema: "schema", Alias: "alias", Name: "name", Column: "column"})
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+func executeTemplate(tmpl *template.Template, data *TemplateData) (string, error) {
+	var buf bytes.Buffer
+
+	err := tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", fmt.Errorf("execute fullname template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// ParseMapping parses a comma separated list of `from=to` pairs.
+// A value enclosed in double quotes can contain commas, e.g. `regions="'EU','US'"`. The quotes are not part of the value.
+func ParseMapping(value string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	pairs, err := splitPairs(value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		from, to, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+			return nil, fmt.Errorf("invalid mapping %q: expected <from>=<to>", pair)
+		}
+
+		to = strings.TrimSpace(to)
+		if len(to) >= 2 && strings.HasPrefix(to, `"`) && strings.HasSuffix(to, `"`) {
+			to = to[1 : len(to)-1]
+		}
+
+		result[strings.TrimSpace(from)] = to
+	}
+
+	return result, nil
+}
+
+// splitPairs splits a comma separated list, ignoring the commas enclosed in double quotes.
+func splitPairs(value string) ([]string, error) {
+	var result []string
+
+	quoted := false
+	start := 0
+
+	for i, r := range value {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			result = append(result, value[start:i])
+			start = i + 1
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("invalid mapping %q: unterminated double quote", value)
+	}
+
+	return append(result, value[start:]), nil
+}
@@ -0,0 +1,143 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+func TestNamer_Template(t *testing.T) {
+	m := &manifest.Manifest{Metadata: manifest.Metadata{AdapterType: "snowflake", ProjectName: "shop"}}
+	node := &manifest.Node{Database: "dev_analytics", Schema: "dbt_jdoe_finance", Name: "orders", Alias: "fct_orders"}
+	column := &manifest.Column{Name: "email"}
+
+	tests := []struct {
+		name       string
+		template   string
+		options    []func(namer *Namer)
+		wantDo     string
+		wantColumn string
+	}{
+		{
+			name:       "without template",
+			options:    []func(namer *Namer){WithDatabaseMapping(map[string]string{"dev_analytics": "analytics"}), WithSchemaMapping(map[string]string{"dbt_jdoe_finance": "finance"})},
+			wantDo:     "prefix.ANALYTICS.FINANCE.FCT_ORDERS",
+			wantColumn: "prefix.ANALYTICS.FINANCE.FCT_ORDERS.EMAIL",
+		},
+		{
+			name:       "insert catalog and use alias",
+			template:   "catalog.{{.Schema}}.{{.Alias}}",
+			wantDo:     "catalog.DBT_JDOE_FINANCE.FCT_ORDERS",
+			wantColumn: "catalog.DBT_JDOE_FINANCE.FCT_ORDERS.EMAIL",
+		},
+		{
+			name:       "column in template",
+			template:   "{{.Prefix}}{{.Database}}/{{.Schema}}/{{.Alias}}{{with .Column}}#{{.}}{{end}}",
+			options:    []func(namer *Namer){WithDatabaseMapping(map[string]string{"dev_analytics": "analytics"})},
+			wantDo:     "prefix.ANALYTICS/DBT_JDOE_FINANCE/FCT_ORDERS",
+			wantColumn: "prefix.ANALYTICS/DBT_JDOE_FINANCE/FCT_ORDERS#EMAIL",
+		},
+		{
+			name:       "functions",
+			template:   `{{.Adapter}}-{{.Project}}.{{lower .Schema | trimPrefix "dbt_jdoe_"}}.{{.Name | replace "O" "0" | lower}}`,
+			wantDo:     "snowflake-shop.finance.0rders",
+			wantColumn: "snowflake-shop.finance.0rders.EMAIL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := tt.options
+
+			if tt.template != "" {
+				tmpl, err := ParseTemplate(tt.template)
+				require.NoError(t, err)
+
+				options = append(options, WithTemplate(tmpl))
+			}
+
+			namer := NewNamer("prefix.", CasingAdapter, options...)
+
+			doName, err := namer.DataObject(m, node)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDo, doName)
+
+			columnName, err := namer.Column(m, node, column)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantColumn, columnName)
+		})
+	}
+}
+
+func TestNamer_Scopes(t *testing.T) {
+	m := &manifest.Manifest{Metadata: manifest.Metadata{AdapterType: "snowflake"}}
+	node := &manifest.Node{Database: "analytics", Schema: "finance", Name: "orders"}
+
+	namerWithTemplate := func(text string) *Namer {
+		tmpl, err := ParseTemplate(text)
+		require.NoError(t, err)
+
+		return NewNamer("prefix.", CasingAdapter, WithTemplate(tmpl))
+	}
+
+	for _, tt := range []struct {
+		name         string
+		namer        *Namer
+		wantSchema   string
+		wantDatabase string
+		wantErr      string
+	}{
+		{name: "without template", namer: NewNamer("prefix.", CasingAdapter), wantSchema: "prefix.ANALYTICS.FINANCE", wantDatabase: "prefix.ANALYTICS"},
+		{name: "catalog prefix", namer: namerWithTemplate("catalog.{{.Database}}.{{.Schema}}.{{.Alias}}"), wantSchema: "catalog.ANALYTICS.FINANCE", wantDatabase: "catalog.ANALYTICS"},
+		{name: "fixed catalog", namer: namerWithTemplate("my_catalog.{{.Schema}}.{{.Alias}}"), wantSchema: "my_catalog.FINANCE", wantDatabase: "my_catalog"},
+		{name: "fixed catalog with table suffix", namer: namerWithTemplate("my_catalog.{{.Schema}}.{{.Alias}}_v1"), wantSchema: "my_catalog.FINANCE", wantDatabase: "my_catalog"},
+		{name: "table prefix", namer: namerWithTemplate("{{.Database}}.{{.Schema}}.tbl_{{.Name | lower}}"), wantSchema: "ANALYTICS.FINANCE", wantDatabase: "ANALYTICS"},
+		{name: "without table", namer: namerWithTemplate("my_catalog.{{.Schema}}"), wantErr: "the fullname template does not use .Alias or .Name"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := tt.namer.Schema(m, node)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSchema, schema)
+
+			database, err := tt.namer.Database(m, node)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDatabase, database)
+		})
+	}
+}
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	for _, tmpl := range []string{"{{.Database", "{{.Catalog}}", "{{unknown .Database}}"} {
+		_, err := ParseTemplate(tmpl)
+		assert.Error(t, err, tmpl)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	mapping, err := ParseMapping("dev_db=prod_db, staging = analytics ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"dev_db": "prod_db", "staging": "analytics"}, mapping)
+
+	mapping, err = ParseMapping("")
+	require.NoError(t, err)
+	assert.Empty(t, mapping)
+
+	_, err = ParseMapping("dev_db")
+	assert.Error(t, err)
+
+	mapping, err = ParseMapping(`regions="'EU','US'", default='EU'`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"regions": "'EU','US'", "default": "'EU'"}, mapping)
+
+	_, err = ParseMapping(`regions="'EU','US'`)
+	assert.ErrorContains(t, err, "unterminated double quote")
+}
@@ -0,0 +1,445 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v3"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+// Policies are grants, filters and masks declared in a policies file, outside the raito meta of the nodes.
+// Each policy targets nodes with a dbt selection, unique ids, or both.
+type Policies struct {
+	Grants  []GrantDeclaration
+	Filters []FilterDeclaration
+	Masks   []MaskDeclaration
+
+	location string
+}
+
+// Target defines the nodes a declared policy applies on.
+type Target struct {
+	Select    string   `json:"select,omitempty"`
+	Exclude   string   `json:"exclude,omitempty"`
+	UniqueIds []string `json:"unique_ids,omitempty"`
+
+	selection *manifest.Selection
+	line      int
+}
+
+type GrantDeclaration struct {
+	manifest.Grant
+	Target
+}
+
+type FilterDeclaration struct {
+	manifest.Filter
+	Target
+}
+
+// MaskDeclaration applies a mask on the columns with the given names of the targeted nodes.
+type MaskDeclaration struct {
+	manifest.Mask
+	Target
+
+	Columns []string `json:"columns"`
+}
+
+// LoadPolicies loads the policies file. Without location, no policies are declared.
+func LoadPolicies(location string) (*Policies, error) {
+	result := &Policies{location: location}
+
+	if location == "" {
+		return result, nil
+	}
+
+	root, err := readYamlDocument(location)
+	if err != nil {
+		return nil, fmt.Errorf("load policies: %w", err)
+	}
+
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("load policies: %s: expected grants, filters and masks", location)
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		switch key.Value {
+		case "grants":
+			result.Grants, err = decodeDeclarations[GrantDeclaration](value)
+		case "filters":
+			result.Filters, err = decodeDeclarations[FilterDeclaration](value)
+		case "masks":
+			result.Masks, err = decodeDeclarations[MaskDeclaration](value)
+		default:
+			err = fmt.Errorf("line %d: unexpected key %q, expected grants, filters or masks", key.Line, key.Value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("load policies: %s: %w", location, err)
+		}
+	}
+
+	err = result.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type declaration interface {
+	GrantDeclaration | FilterDeclaration | MaskDeclaration
+}
+
+func decodeDeclarations[T declaration](node *yaml.Node) ([]T, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("line %d: expected a list", node.Line)
+	}
+
+	result := make([]T, len(node.Content))
+
+	for i, item := range node.Content {
+		err := decodeYaml(item, &result[i])
+		if err != nil {
+			return nil, err
+		}
+
+		target := targetOf(&result[i])
+		target.line = item.Line
+
+		err = target.parse()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", item.Line, err)
+		}
+	}
+
+	return result, nil
+}
+
+func targetOf[T declaration](d *T) *Target {
+	switch v := any(d).(type) {
+	case *GrantDeclaration:
+		return &v.Target
+	case *FilterDeclaration:
+		return &v.Target
+	case *MaskDeclaration:
+		return &v.Target
+	default:
+		return nil
+	}
+}
+
+func (t *Target) parse() error {
+	if t.Select == "" && len(t.UniqueIds) == 0 {
+		return errors.New("select or unique_ids is required")
+	}
+
+	if t.Select == "" {
+		return nil
+	}
+
+	selection, err := manifest.ParseSelection(t.Select, t.Exclude)
+	if err != nil {
+		return fmt.Errorf("select: %w", err)
+	}
+
+	t.selection = selection
+
+	return nil
+}
+
+func (p *Policies) validate() error {
+	for i := range p.Grants {
+		if p.Grants[i].Name == "" && p.Grants[i].Template == "" {
+			return fmt.Errorf("load policies: %s: grant is missing a name or template", p.source(p.Grants[i].line))
+		}
+	}
+
+	for i := range p.Filters {
+		if p.Filters[i].Name == "" && p.Filters[i].Template == "" {
+			return fmt.Errorf("load policies: %s: filter is missing a name or template", p.source(p.Filters[i].line))
+		}
+	}
+
+	for i := range p.Masks {
+		if p.Masks[i].Name == "" || len(p.Masks[i].Columns) == 0 {
+			return fmt.Errorf("load policies: %s: mask is missing a name or columns", p.source(p.Masks[i].line))
+		}
+	}
+
+	return nil
+}
+
+func (p *Policies) source(line int) string {
+	return fmt.Sprintf("%s:%d", p.location, line)
+}
+
+func (p *Policies) targets() []*Target {
+	targets := make([]*Target, 0, len(p.Grants)+len(p.Filters)+len(p.Masks))
+	for i := range p.Grants {
+		targets = append(targets, &p.Grants[i].Target)
+	}
+
+	for i := range p.Filters {
+		targets = append(targets, &p.Filters[i].Target)
+	}
+
+	for i := range p.Masks {
+		targets = append(targets, &p.Masks[i].Target)
+	}
+
+	return targets
+}
+
+// Validate reports the unique ids of the declared policies that are not defined in any manifest of the mesh, as they are most likely a typo.
+// As the unique ids are checked against the whole mesh, Validate should be called once per mesh instead of once per manifest.
+func (p *Policies) Validate(mesh *manifest.Mesh) error {
+	if p == nil {
+		return nil
+	}
+
+	var err error
+
+	for _, target := range p.targets() {
+		for _, uniqueId := range target.UniqueIds {
+			if _, _, found := mesh.Node(uniqueId); !found {
+				err = multierror.Append(err, fmt.Errorf("%s: unique id %q is not defined in the manifest", p.source(target.line), uniqueId))
+			}
+		}
+	}
+
+	return err
+}
+
+// Resolve evaluates the targets of the declared policies on a manifest of the mesh.
+func (p *Policies) Resolve(manifestData *manifest.Manifest) (*ResolvedPolicies, error) {
+	if p == nil {
+		return &ResolvedPolicies{policies: &Policies{}}, nil
+	}
+
+	result := &ResolvedPolicies{policies: p, targets: make(map[*Target]map[string]struct{})}
+
+	for _, target := range p.targets() {
+		uniqueIds := make(map[string]struct{}, len(target.UniqueIds))
+
+		for _, uniqueId := range target.UniqueIds {
+			uniqueIds[uniqueId] = struct{}{}
+		}
+
+		if target.selection != nil {
+			nodes, err := manifestData.SelectDataObjectNodes(target.selection)
+			if err != nil {
+				return nil, fmt.Errorf("%s: select: %w", p.source(target.line), err)
+			}
+
+			for _, node := range nodes {
+				uniqueIds[node.UniqueId] = struct{}{}
+			}
+		}
+
+		result.targets[target] = uniqueIds
+	}
+
+	return result, nil
+}
+
+// ResolvedPolicies are the declared policies with the nodes of a manifest they apply on.
+type ResolvedPolicies struct {
+	policies *Policies
+	targets  map[*Target]map[string]struct{}
+}
+
+// Conflict is a policy that is defined twice on a node, with a different definition. Only the definition at Used is applied.
+type Conflict struct {
+	Policy  string
+	Node    string
+	Used    string
+	Ignored string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s of %s is defined in %s and in %s, the definition in %s is used", c.Policy, c.Node, c.Used, c.Ignored, c.Used)
+}
+
+// Apply returns the node with the declared policies that target the node merged into its raito meta. The node itself is not modified.
+// Policies defined in the raito meta take precedence over declared policies with the same name (or template). If multiple declarations
+// define the same policy, the first one is used. A policy that is defined twice with a different definition is returned as conflict.
+func (r *ResolvedPolicies) Apply(node *manifest.Node) (*manifest.Node, []Conflict) {
+	if r == nil || !r.hasPolicies(node) {
+		return node, nil
+	}
+
+	result := *node
+	result.Meta.Raito.Grant = append([]manifest.Grant(nil), node.Meta.Raito.Grant...)
+	result.Meta.Raito.Filter = append([]manifest.Filter(nil), node.Meta.Raito.Filter...)
+	result.Columns = make(map[string]manifest.Column, len(node.Columns))
+
+	var conflicts []Conflict
+
+	conflict := func(policy string, used string, ignored string, equal bool) {
+		if !equal {
+			conflicts = append(conflicts, Conflict{Policy: policy, Node: node.UniqueId, Used: used, Ignored: ignored})
+		}
+	}
+
+	grantSources := make(map[string]string)
+	for _, grant := range node.Meta.Raito.Grant {
+		grantSources[grantKey(&grant)] = metaSource(node)
+	}
+
+	for i := range r.policies.Grants {
+		declared := &r.policies.Grants[i]
+		if !r.applies(&declared.Target, node) {
+			continue
+		}
+
+		key := grantKey(&declared.Grant)
+		if used, found := grantSources[key]; found {
+			conflict("grant "+key, used, r.policies.source(declared.line), reflect.DeepEqual(findGrant(result.Meta.Raito.Grant, key), &declared.Grant))
+
+			continue
+		}
+
+		grantSources[key] = r.policies.source(declared.line)
+		result.Meta.Raito.Grant = append(result.Meta.Raito.Grant, declared.Grant)
+	}
+
+	filterSources := make(map[string]string)
+	for _, filter := range node.Meta.Raito.Filter {
+		filterSources[filterKey(&filter)] = metaSource(node)
+	}
+
+	for i := range r.policies.Filters {
+		declared := &r.policies.Filters[i]
+		if !r.applies(&declared.Target, node) {
+			continue
+		}
+
+		key := filterKey(&declared.Filter)
+		if used, found := filterSources[key]; found {
+			conflict("filter "+key, used, r.policies.source(declared.line), reflect.DeepEqual(findFilter(result.Meta.Raito.Filter, key), &declared.Filter))
+
+			continue
+		}
+
+		filterSources[key] = r.policies.source(declared.line)
+		result.Meta.Raito.Filter = append(result.Meta.Raito.Filter, declared.Filter)
+	}
+
+	for key, column := range node.Columns {
+		name := column.Name
+		if name == "" {
+			name = key
+		}
+
+		maskSource := ""
+		if column.Meta.Raito.Mask != nil {
+			maskSource = metaSource(node)
+		}
+
+		for i := range r.policies.Masks {
+			declared := &r.policies.Masks[i]
+			if !declared.masks(name) || !r.applies(&declared.Target, node) {
+				continue
+			}
+
+			if maskSource != "" {
+				conflict(fmt.Sprintf("mask of column %s", name), maskSource, r.policies.source(declared.line), reflect.DeepEqual(column.Meta.Raito.Mask, &declared.Mask))
+
+				continue
+			}
+
+			mask := declared.Mask
+			column.Meta.Raito.Mask = &mask
+			maskSource = r.policies.source(declared.line)
+		}
+
+		result.Columns[key] = column
+	}
+
+	return &result, conflicts
+}
+
+// hasPolicies returns true if any declared policy applies on the node.
+func (r *ResolvedPolicies) hasPolicies(node *manifest.Node) bool {
+	for _, uniqueIds := range r.targets {
+		if _, found := uniqueIds[node.UniqueId]; found {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *ResolvedPolicies) applies(target *Target, node *manifest.Node) bool {
+	_, found := r.targets[target][node.UniqueId]
+
+	return found
+}
+
+func (m *MaskDeclaration) masks(column string) bool {
+	for _, name := range m.Columns {
+		if strings.EqualFold(name, column) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// metaSource returns the location of the raito meta of the node: the properties file of the node if any, otherwise the node file.
+func metaSource(node *manifest.Node) string {
+	location := node.OriginalFilePath
+	if node.PatchPath != "" {
+		location = node.PatchPath
+
+		if _, path, found := strings.Cut(location, "://"); found {
+			location = path
+		}
+	}
+
+	return fmt.Sprintf("meta of %s", location)
+}
+
+func filterKey(filter *manifest.Filter) string {
+	if filter.Template != "" {
+		return fmt.Sprintf("template %s", filter.Template)
+	}
+
+	return filter.Name
+}
+
+func grantKey(grant *manifest.Grant) string {
+	if grant.Template != "" {
+		return fmt.Sprintf("template %s", grant.Template)
+	}
+
+	return grant.Name
+}
+
+func findGrant(grants []manifest.Grant, key string) *manifest.Grant {
+	for i := range grants {
+		if grantKey(&grants[i]) == key {
+			return &grants[i]
+		}
+	}
+
+	return nil
+}
+
+func findFilter(filters []manifest.Filter, key string) *manifest.Filter {
+	for i := range filters {
+		if filterKey(&filters[i]) == key {
+			return &filters[i]
+		}
+	}
+
+	return nil
+}
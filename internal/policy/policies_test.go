@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/raito-io/bexpression/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+func TestLoadPolicies(t *testing.T) {
+	policies, err := LoadPolicies("testdata/policies.yml")
+	require.NoError(t, err)
+
+	require.Len(t, policies.Grants, 2)
+	assert.Equal(t, manifest.Grant{Name: "finance_readers", Permissions: []string{"SELECT"}}, policies.Grants[0].Grant)
+	assert.Equal(t, "tag:finance", policies.Grants[0].Select)
+	assert.Equal(t, []string{"model.shop.orders"}, policies.Grants[1].UniqueIds)
+	require.Len(t, policies.Filters, 1)
+	assert.Equal(t, "region", policies.Filters[0].Template)
+	require.Len(t, policies.Masks, 1)
+	assert.Equal(t, []string{"email"}, policies.Masks[0].Columns)
+
+	t.Run("without location", func(t *testing.T) {
+		policies, err := LoadPolicies("")
+		require.NoError(t, err)
+		assert.Empty(t, policies.Grants)
+	})
+
+	t.Run("without target", func(t *testing.T) {
+		_, err := LoadPolicies("testdata/invalid_policies.yml")
+		assert.EqualError(t, err, "load policies: testdata/invalid_policies.yml: line 2: select or unique_ids is required")
+	})
+}
+
+func TestResolvedPolicies_Apply(t *testing.T) {
+	policies, err := LoadPolicies("testdata/policies.yml")
+	require.NoError(t, err)
+
+	manifestData := &manifest.Manifest{Nodes: map[string]manifest.Node{
+		"model.shop.orders": {UniqueId: "model.shop.orders", Name: "orders", ResourceType: "model", Tags: []string{"finance", "regional"}, PatchPath: "shop://models/orders.yml",
+			Meta: manifest.Meta{Raito: manifest.RaitoMeta{Filter: []manifest.Filter{{Template: "region", PolicyRule: "region = 'EU'"}}}},
+			Columns: map[string]manifest.Column{
+				"email": {Name: "email", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: "email_mask", Type: utils.Ptr("SHA256")}}}},
+			}},
+		"model.shop.orders_archive": {UniqueId: "model.shop.orders_archive", Name: "orders_archive", ResourceType: "model", Tags: []string{"finance"}},
+		"model.shop.customers": {UniqueId: "model.shop.customers", Name: "customers", ResourceType: "model", OriginalFilePath: "models/customers.sql",
+			Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{{Name: "finance_readers", Permissions: []string{"SELECT"}}}}},
+			Columns: map[string]manifest.Column{
+				"EMAIL": {Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: "email_mask"}}}},
+				"name":  {Name: "name"},
+			}},
+		"model.shop.products": {UniqueId: "model.shop.products", Name: "products", ResourceType: "model"},
+	}}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+	require.NoError(t, policies.Validate(mesh))
+
+	resolved, err := policies.Resolve(manifestData)
+	require.NoError(t, err)
+
+	t.Run("declared policies with conflicts", func(t *testing.T) {
+		node := manifestData.Nodes["model.shop.orders"]
+
+		got, conflicts := resolved.Apply(&node)
+
+		assert.Equal(t, []manifest.Grant{{Name: "finance_readers", Permissions: []string{"SELECT"}}}, got.Meta.Raito.Grant)
+		assert.Equal(t, []manifest.Filter{{Template: "region", PolicyRule: "region = 'EU'"}}, got.Meta.Raito.Filter)
+		assert.Equal(t, &manifest.Mask{Name: "email_mask", Type: utils.Ptr("SHA256")}, got.Columns["email"].Meta.Raito.Mask)
+
+		require.Len(t, conflicts, 2)
+		assert.Equal(t, "grant finance_readers of model.shop.orders is defined in testdata/policies.yml:2 and in testdata/policies.yml:7, the definition in testdata/policies.yml:2 is used", conflicts[0].String())
+		assert.Equal(t, "filter template region of model.shop.orders is defined in meta of models/orders.yml and in testdata/policies.yml:14, the definition in meta of models/orders.yml is used", conflicts[1].String())
+	})
+
+	t.Run("meta takes precedence", func(t *testing.T) {
+		node := manifestData.Nodes["model.shop.customers"]
+
+		got, conflicts := resolved.Apply(&node)
+
+		assert.Equal(t, node.Meta.Raito.Grant, got.Meta.Raito.Grant)
+		assert.Equal(t, &manifest.Mask{Name: "email_mask"}, got.Columns["EMAIL"].Meta.Raito.Mask)
+		assert.Nil(t, got.Columns["name"].Meta.Raito.Mask)
+
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, "mask of column EMAIL of model.shop.customers is defined in meta of models/customers.sql and in testdata/policies.yml:17, the definition in meta of models/customers.sql is used", conflicts[0].String())
+	})
+
+	t.Run("excluded node", func(t *testing.T) {
+		node := manifestData.Nodes["model.shop.orders_archive"]
+
+		got, conflicts := resolved.Apply(&node)
+
+		assert.Same(t, &node, got)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("without policies", func(t *testing.T) {
+		var noPolicies *Policies
+
+		resolved, err := noPolicies.Resolve(manifestData)
+		require.NoError(t, err)
+
+		node := manifestData.Nodes["model.shop.products"]
+
+		got, conflicts := resolved.Apply(&node)
+		assert.Same(t, &node, got)
+		assert.Empty(t, conflicts)
+	})
+}
+
+func TestPolicies_Validate(t *testing.T) {
+	policies, err := LoadPolicies("testdata/policies.yml")
+	require.NoError(t, err)
+
+	manifestData := &manifest.Manifest{Nodes: map[string]manifest.Node{
+		"model.shop.orders": {UniqueId: "model.shop.orders", Name: "orders", ResourceType: "model"},
+	}}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	err = policies.Validate(mesh)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `testdata/policies.yml:17: unique id "model.shop.customers" is not defined in the manifest`)
+	assert.NotContains(t, err.Error(), `"model.shop.orders"`)
+}
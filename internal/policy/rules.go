@@ -0,0 +1,207 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/expression"
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+// Rules define policies for all nodes and columns whose tags or meta match a predicate, instead of defining them in the raito meta of each node.
+type Rules struct {
+	Rules []Rule `json:"rules"`
+
+	tagSplitKey string
+}
+
+// Rule adds a grant or filter to the nodes, or a mask to the columns, that match the predicate.
+type Rule struct {
+	Match  string           `json:"match"`
+	Grant  *manifest.Grant  `json:"grant,omitempty"`
+	Filter *manifest.Filter `json:"filter,omitempty"`
+	Mask   *manifest.Mask   `json:"mask,omitempty"`
+
+	rule *expression.TagRule
+}
+
+// LoadRules loads the rules file. Without location, no rules are defined.
+// Tags are split in a key and value by the tag split key, in the same way as the tag syncer imports them.
+func LoadRules(ctx context.Context, location string, tagSplitKey string) (*Rules, error) {
+	result := &Rules{tagSplitKey: tagSplitKey}
+
+	if location == "" {
+		return result, nil
+	}
+
+	err := readYaml(location, result)
+	if err != nil {
+		return nil, fmt.Errorf("load rules: %w", err)
+	}
+
+	var ruleErr error
+
+	for i := range result.Rules {
+		validateErr := result.Rules[i].validate(ctx)
+		if validateErr != nil {
+			ruleErr = multierror.Append(ruleErr, fmt.Errorf("rule %d: %w", i+1, validateErr))
+		}
+	}
+
+	if ruleErr != nil {
+		return nil, fmt.Errorf("load rules: %w", ruleErr)
+	}
+
+	return result, nil
+}
+
+func (r *Rule) validate(ctx context.Context) error {
+	policies := 0
+
+	for _, defined := range []bool{r.Grant != nil, r.Filter != nil, r.Mask != nil} {
+		if defined {
+			policies++
+		}
+	}
+
+	if policies != 1 {
+		return errors.New("expected exactly one grant, filter or mask")
+	}
+
+	if r.Filter != nil && r.Filter.Template == "" {
+		return errors.New("filter should refer to a template, so one filter is created per model")
+	}
+
+	if (r.Grant != nil && r.Grant.Name == "" && r.Grant.Template == "") || (r.Mask != nil && r.Mask.Name == "") {
+		return errors.New("name is missing")
+	}
+
+	rule, err := expression.ParseTagRule(ctx, r.Match)
+	if err != nil {
+		return fmt.Errorf("match: %w", err)
+	}
+
+	r.rule = rule
+
+	return nil
+}
+
+// Apply returns the node with the policies of the matching rules merged into its raito meta. The node itself is not modified.
+// Grant and filter rules are matched with the tags and meta of the node, mask rules with the tags and meta of each column.
+// Policies defined in the raito meta take precedence: a rule does not add a grant or filter that is already defined on the node,
+// nor a mask on a column that already has a mask. If multiple mask rules match a column, the first one is used.
+func (r *Rules) Apply(node *manifest.Node) *manifest.Node {
+	if r == nil || len(r.Rules) == 0 {
+		return node
+	}
+
+	result := *node
+	result.Meta.Raito.Grant = append([]manifest.Grant(nil), node.Meta.Raito.Grant...)
+	result.Meta.Raito.Filter = append([]manifest.Filter(nil), node.Meta.Raito.Filter...)
+	result.Columns = make(map[string]manifest.Column, len(node.Columns))
+
+	nodeTags := r.tags(node.Tags, node.Config.Tags, manifest.MergedMeta(&node.Meta, node.Config.Meta))
+
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+
+		switch {
+		case rule.Grant != nil:
+			if findGrant(result.Meta.Raito.Grant, grantKey(rule.Grant)) == nil && rule.matches(nodeTags) {
+				result.Meta.Raito.Grant = append(result.Meta.Raito.Grant, *rule.Grant)
+			}
+		case rule.Filter != nil:
+			if !hasFilter(result.Meta.Raito.Filter, rule.Filter.Template) && rule.matches(nodeTags) {
+				result.Meta.Raito.Filter = append(result.Meta.Raito.Filter, *rule.Filter)
+			}
+		}
+	}
+
+	for key, column := range node.Columns {
+		if column.Meta.Raito.Mask == nil {
+			columnTags := r.tags(column.Tags, column.Config.Tags, manifest.MergedMeta(&column.Meta, column.Config.Meta))
+
+			for i := range r.Rules {
+				if r.Rules[i].Mask != nil && r.Rules[i].matches(columnTags) {
+					mask := *r.Rules[i].Mask
+					column.Meta.Raito.Mask = &mask
+
+					break
+				}
+			}
+		}
+
+		result.Columns[key] = column
+	}
+
+	return &result
+}
+
+func (r *Rule) matches(tags map[string]map[string]struct{}) bool {
+	return expression.MatchTagRule(r.rule, func(key string, value string) bool {
+		_, found := tags[key][value]
+
+		return found
+	})
+}
+
+// tags returns the values per key of the tags and the meta of a node or column. A meta value that is a list matches each of its items.
+func (r *Rules) tags(tags []string, configTags []string, meta map[string]interface{}) map[string]map[string]struct{} {
+	result := make(map[string]map[string]struct{})
+
+	add := func(key string, value string) {
+		if result[key] == nil {
+			result[key] = make(map[string]struct{})
+		}
+
+		result[key][value] = struct{}{}
+	}
+
+	for _, tag := range append(append([]string(nil), tags...), configTags...) {
+		if key, value, found := strings.Cut(tag, r.tagSplitKey); r.tagSplitKey != "" && found {
+			add(key, value)
+		} else {
+			add(expression.DefaultTagKey, tag)
+		}
+	}
+
+	for key, value := range meta {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				if isScalar(item) {
+					add(key, fmt.Sprint(item))
+				}
+			}
+		default:
+			if isScalar(v) {
+				add(key, fmt.Sprint(v))
+			}
+		}
+	}
+
+	return result
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, int64, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func hasFilter(filters []manifest.Filter, template string) bool {
+	for i := range filters {
+		if filters[i].Template == template {
+			return true
+		}
+	}
+
+	return false
+}
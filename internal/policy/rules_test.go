@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raito-io/bexpression/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+func TestLoadRules(t *testing.T) {
+	rules, err := LoadRules(context.Background(), "testdata/rules.yml", ":")
+	require.NoError(t, err)
+	assert.Len(t, rules.Rules, 4)
+
+	t.Run("without location", func(t *testing.T) {
+		rules, err := LoadRules(context.Background(), "", "")
+		require.NoError(t, err)
+		assert.Empty(t, rules.Rules)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		_, err := LoadRules(context.Background(), "testdata/invalid_rules.yml", "")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "rule 1: expected exactly one grant, filter or mask")
+		assert.ErrorContains(t, err, "rule 2: filter should refer to a template")
+		assert.ErrorContains(t, err, "rule 3: match: invalid expression")
+	})
+}
+
+func TestRules_Apply(t *testing.T) {
+	rules, err := LoadRules(context.Background(), "testdata/rules.yml", ":")
+	require.NoError(t, err)
+
+	financeReaders := manifest.Grant{Name: "finance_readers", Permissions: []string{"SELECT"}}
+	piiHash := &manifest.Mask{Name: "pii_hash", Type: utils.Ptr("SHA256")}
+
+	tests := []struct {
+		name        string
+		node        manifest.Node
+		wantGrants  []manifest.Grant
+		wantFilters []manifest.Filter
+		wantMasks   map[string]*manifest.Mask
+	}{
+		{
+			name: "tags of node and columns",
+			node: manifest.Node{
+				Tags:   []string{"finance"},
+				Config: manifest.NodeConfig{Tags: []string{"regional"}},
+				Columns: map[string]manifest.Column{
+					"email":   {Name: "email", Tags: []string{"email", "pii"}},
+					"address": {Name: "address", Config: manifest.NodeConfig{Meta: map[string]interface{}{"classification": []interface{}{"confidential"}}}},
+					"amount":  {Name: "amount"},
+				},
+			},
+			wantGrants:  []manifest.Grant{financeReaders},
+			wantFilters: []manifest.Filter{{Template: "region"}},
+			wantMasks:   map[string]*manifest.Mask{"email": piiHash, "address": piiHash, "amount": nil},
+		},
+		{
+			name: "meta and split tags",
+			node: manifest.Node{
+				Tags:   []string{"regional", "domain:global"},
+				Config: manifest.NodeConfig{Meta: map[string]interface{}{"domain": "finance"}},
+			},
+			wantGrants: []manifest.Grant{financeReaders},
+		},
+		{
+			name: "raito meta takes precedence",
+			node: manifest.Node{
+				Tags: []string{"finance", "regional"},
+				Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+					Grant:  []manifest.Grant{{Name: "finance_readers", Permissions: []string{"SELECT", "INSERT"}}},
+					Filter: []manifest.Filter{{Template: "region", PolicyRule: "region = 'EU'"}},
+				}},
+				Columns: map[string]manifest.Column{
+					"email": {Name: "email", Tags: []string{"pii"}, Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: "email_mask"}}}},
+				},
+			},
+			wantGrants:  []manifest.Grant{{Name: "finance_readers", Permissions: []string{"SELECT", "INSERT"}}},
+			wantFilters: []manifest.Filter{{Template: "region", PolicyRule: "region = 'EU'"}},
+			wantMasks:   map[string]*manifest.Mask{"email": {Name: "email_mask"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.node
+
+			got := rules.Apply(&tt.node)
+
+			assert.Equal(t, tt.wantGrants, got.Meta.Raito.Grant)
+			assert.Equal(t, tt.wantFilters, got.Meta.Raito.Filter)
+
+			for column, wantMask := range tt.wantMasks {
+				assert.Equal(t, wantMask, got.Columns[column].Meta.Raito.Mask, column)
+			}
+
+			assert.Equal(t, original, tt.node)
+		})
+	}
+
+	t.Run("column meta of a manifest", func(t *testing.T) {
+		manifestData, loadErr := manifest.NewManifestParser().LoadManifest(context.Background(), "testdata/manifest_column_meta.json")
+		require.NoError(t, loadErr)
+
+		node, found := manifestData.Node("model.dbt_bq_demo.new_customers")
+		require.True(t, found)
+
+		got := rules.Apply(node)
+
+		assert.Equal(t, piiHash, got.Columns["Email"].Meta.Raito.Mask)
+		assert.Nil(t, got.Columns["Country"].Meta.Raito.Mask)
+	})
+
+	t.Run("without rules", func(t *testing.T) {
+		node := &manifest.Node{Tags: []string{"finance"}}
+
+		var noRules *Rules
+
+		assert.Same(t, node, noRules.Apply(node))
+	})
+}
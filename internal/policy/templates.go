@@ -0,0 +1,193 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+// Templates are reusable policies, defined once and referenced by name from the raito meta of the nodes.
+type Templates struct {
+	Grants  map[string]manifest.Grant  `json:"grants"`
+	Filters map[string]manifest.Filter `json:"filters"`
+}
+
+// LoadTemplates loads the templates file. Without location, no templates are defined.
+func LoadTemplates(location string) (*Templates, error) {
+	result := &Templates{}
+
+	if location == "" {
+		return result, nil
+	}
+
+	err := readYaml(location, result)
+	if err != nil {
+		return nil, fmt.Errorf("load templates: %w", err)
+	}
+
+	for name, grant := range result.Grants {
+		if grant.Template != "" {
+			return nil, fmt.Errorf("load templates: grant template %q can not refer to another template", name)
+		}
+	}
+
+	for name, filter := range result.Filters {
+		if filter.Template != "" {
+			return nil, fmt.Errorf("load templates: filter template %q can not refer to another template", name)
+		}
+	}
+
+	return result, nil
+}
+
+// ExpandGrants replaces the grants of the node that refer to a template by the template. The name of an expanded grant is the name
+// defined in the template, or the name of the template, so all nodes referring to the template share one grant. Permissions and
+// global permissions defined on the node are added to the permissions of the template, other properties defined on the node override the template.
+func (t *Templates) ExpandGrants(node *manifest.Node) ([]manifest.Grant, error) {
+	result := make([]manifest.Grant, 0, len(node.Meta.Raito.Grant))
+
+	for _, grant := range node.Meta.Raito.Grant {
+		if grant.Template == "" {
+			result = append(result, grant)
+
+			continue
+		}
+
+		var template manifest.Grant
+
+		found := false
+		if t != nil {
+			template, found = t.Grants[grant.Template]
+		}
+
+		if !found {
+			return nil, fmt.Errorf("grant template %q of %s not found", grant.Template, node.UniqueId)
+		}
+
+		if template.Name == "" {
+			template.Name = grant.Template
+		}
+
+		if grant.Name != "" {
+			template.Name = grant.Name
+		}
+
+		template.Permissions = union(template.Permissions, grant.Permissions)
+		template.GlobalPermissions = union(template.GlobalPermissions, grant.GlobalPermissions)
+
+		overrideGrant(&template, &grant)
+
+		result = append(result, template)
+	}
+
+	return result, nil
+}
+
+func overrideGrant(template *manifest.Grant, grant *manifest.Grant) {
+	if len(grant.Owners) > 0 {
+		template.Owners = grant.Owners
+	}
+
+	if grant.Category != nil {
+		template.Category = grant.Category
+	}
+
+	if grant.Type != nil {
+		template.Type = grant.Type
+	}
+
+	if len(grant.Refs) > 0 {
+		template.Refs = grant.Refs
+	}
+
+	if grant.Scope != "" {
+		template.Scope = grant.Scope
+	}
+
+	if grant.WhatRule != "" {
+		template.WhatRule, template.WhatDoTypes = grant.WhatRule, grant.WhatDoTypes
+	}
+
+	if grant.WhoRule != "" {
+		template.WhoRule = grant.WhoRule
+	}
+
+	if grant.Propagate != "" {
+		template.Propagate, template.PropagateDepth = grant.Propagate, grant.PropagateDepth
+	}
+}
+
+// union returns the values of both lists, without duplicates and in order of appearance.
+func union(values []string, other []string) []string {
+	var result []string
+
+	seen := make(map[string]struct{}, len(values)+len(other))
+
+	for _, value := range append(append([]string(nil), values...), other...) {
+		if _, found := seen[value]; !found {
+			seen[value] = struct{}{}
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// ExpandFilters replaces the filters of the node that refer to a template by the template. The name of an expanded filter is
+// `<template>-<model>`, so one filter is created per node (see ExpandedName). Policy rule, criteria, who rule and owners defined on the node override the template.
+func (t *Templates) ExpandFilters(projectName string, node *manifest.Node) ([]manifest.Filter, error) {
+	result := make([]manifest.Filter, 0, len(node.Meta.Raito.Filter))
+
+	for _, filter := range node.Meta.Raito.Filter {
+		if filter.Template == "" {
+			result = append(result, filter)
+
+			continue
+		}
+
+		var template manifest.Filter
+
+		found := false
+		if t != nil {
+			template, found = t.Filters[filter.Template]
+		}
+
+		if !found {
+			return nil, fmt.Errorf("filter template %q of %s not found", filter.Template, node.UniqueId)
+		}
+
+		template.Name = ExpandedName(filter.Template, projectName, node)
+
+		if filter.PolicyRule != "" || filter.Criteria != "" {
+			template.PolicyRule, template.Criteria = filter.PolicyRule, filter.Criteria
+		}
+
+		if filter.WhoRule != "" {
+			template.WhoRule = filter.WhoRule
+		}
+
+		if len(filter.Owners) > 0 {
+			template.Owners = filter.Owners
+		}
+
+		result = append(result, template)
+	}
+
+	return result, nil
+}
+
+// ExpandedName returns the name of a policy expanded from a template, or split per table, for the node of the given project.
+// Model names are only unique within a package, so nodes of other packages are prefixed with their package name.
+// Each version of a versioned model gets its own name.
+func ExpandedName(template string, projectName string, node *manifest.Node) string {
+	name := node.Name
+	if node.PackageName != "" && node.PackageName != projectName {
+		name = fmt.Sprintf("%s-%s", node.PackageName, name)
+	}
+
+	if node.Version != "" {
+		return fmt.Sprintf("%s-%s-v%s", template, name, node.Version)
+	}
+
+	return fmt.Sprintf("%s-%s", template, name)
+}
@@ -0,0 +1,160 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/raito-io/bexpression/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	templates, err := LoadTemplates("testdata/templates.yml")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]manifest.Filter{
+		"region": {PolicyRule: "{{ this.column.region }} IN ({{ var.regions }})", Owners: []string{"governance@example.com"}},
+		"active": {Criteria: "is_active = true"},
+	}, templates.Filters)
+
+	t.Run("without location", func(t *testing.T) {
+		templates, err := LoadTemplates("")
+		require.NoError(t, err)
+		assert.Empty(t, templates.Filters)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadTemplates("testdata/missing.yml")
+		assert.ErrorContains(t, err, "read testdata/missing.yml")
+	})
+}
+
+func TestTemplates_ExpandFilters(t *testing.T) {
+	templates, err := LoadTemplates("testdata/templates.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		node    manifest.Node
+		want    []manifest.Filter
+		wantErr string
+	}{
+		{
+			name: "template and inline filter",
+			node: manifest.Node{UniqueId: "model.shop.orders", Name: "orders", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Filter: []manifest.Filter{
+				{Template: "region"},
+				{Name: "big_orders", PolicyRule: "amount > 1000"},
+			}}}},
+			want: []manifest.Filter{
+				{Name: "region-orders", PolicyRule: "{{ this.column.region }} IN ({{ var.regions }})", Owners: []string{"governance@example.com"}},
+				{Name: "big_orders", PolicyRule: "amount > 1000"},
+			},
+		},
+		{
+			name: "overrides of a versioned model",
+			node: manifest.Node{UniqueId: "model.shop.customers.v2", Name: "customers", Version: "2", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Filter: []manifest.Filter{
+				{Template: "region", PolicyRule: "country = 'BE'", Owners: []string{"sales@example.com"}, WhoRule: "department = Sales"},
+			}}}},
+			want: []manifest.Filter{
+				{Name: "region-customers-v2", PolicyRule: "country = 'BE'", Owners: []string{"sales@example.com"}, WhoRule: "department = Sales"},
+			},
+		},
+		{
+			name: "model of another package",
+			node: manifest.Node{UniqueId: "model.shop_utils.orders", Name: "orders", PackageName: "shop_utils", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Filter: []manifest.Filter{
+				{Template: "region"},
+			}}}},
+			want: []manifest.Filter{
+				{Name: "region-shop_utils-orders", PolicyRule: "{{ this.column.region }} IN ({{ var.regions }})", Owners: []string{"governance@example.com"}},
+			},
+		},
+		{
+			name: "unknown template",
+			node: manifest.Node{UniqueId: "model.shop.orders", Name: "orders", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Filter: []manifest.Filter{
+				{Template: "country"},
+			}}}},
+			wantErr: `filter template "country" of model.shop.orders not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templates.ExpandFilters("shop", &tt.node)
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTemplates_ExpandGrants(t *testing.T) {
+	templates, err := LoadTemplates("testdata/templates.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		node    manifest.Node
+		want    []manifest.Grant
+		wantErr string
+	}{
+		{
+			name: "template and inline grant",
+			node: manifest.Node{UniqueId: "model.shop.orders", Name: "orders", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{
+				{Template: "finance_read"},
+				{Template: "marketing_read"},
+				{Name: "sales_readers", Permissions: []string{"SELECT"}},
+			}}}},
+			want: []manifest.Grant{
+				{Name: "finance_read", Permissions: []string{"SELECT"}, Category: utils.Ptr("finance"), Owners: []string{"finance@example.com"}},
+				{Name: "marketing_readers", Permissions: []string{"SELECT"}},
+				{Name: "sales_readers", Permissions: []string{"SELECT"}},
+			},
+		},
+		{
+			name: "overrides",
+			node: manifest.Node{UniqueId: "model.shop.orders", Name: "orders", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{
+				{Template: "finance_read", Permissions: []string{"SELECT", "INSERT"}, GlobalPermissions: []string{"read"}, Owners: []string{"sales@example.com"}, Scope: manifest.GrantScopeSchema},
+			}}}},
+			want: []manifest.Grant{
+				{Name: "finance_read", Permissions: []string{"SELECT", "INSERT"}, GlobalPermissions: []string{"read"}, Category: utils.Ptr("finance"), Owners: []string{"sales@example.com"}, Scope: manifest.GrantScopeSchema},
+			},
+		},
+		{
+			name: "unknown template",
+			node: manifest.Node{UniqueId: "model.shop.orders", Name: "orders", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{
+				{Template: "hr_read"},
+			}}}},
+			wantErr: `grant template "hr_read" of model.shop.orders not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templates.ExpandGrants(&tt.node)
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("without templates", func(t *testing.T) {
+		var noTemplates *Templates
+
+		_, err := noTemplates.ExpandGrants(&manifest.Node{UniqueId: "model.shop.orders", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{{Template: "finance_read"}}}}})
+		require.EqualError(t, err, `grant template "finance_read" of model.shop.orders not found`)
+	})
+}
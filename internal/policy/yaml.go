@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readYaml reads a yaml (or json) file into v. The file is converted to json first, so the json tags of the manifest types are reused.
+func readYaml(location string, v interface{}) error {
+	document, err := readYamlDocument(location)
+	if err != nil {
+		return err
+	}
+
+	err = decodeYaml(document, v)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", location, err)
+	}
+
+	return nil
+}
+
+// readYamlDocument reads a yaml (or json) file and returns its root node, which keeps the line numbers of the file.
+func readYamlDocument(location string) (*yaml.Node, error) {
+	data, err := os.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", location, err)
+	}
+
+	var document yaml.Node
+
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", location, err)
+	}
+
+	if len(document.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode}, nil
+	}
+
+	return document.Content[0], nil
+}
+
+// decodeYaml decodes a yaml node into v, using the json tags of v.
+func decodeYaml(node *yaml.Node, v interface{}) error {
+	var content interface{}
+
+	err := node.Decode(&content)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	jsonData, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	err = json.Unmarshal(jsonData, v)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package resource_provider
+
+import (
+	"github.com/raito-io/bexpression/base"
+	"github.com/raito-io/bexpression/datacomparison"
+	"github.com/raito-io/bexpression/utils"
+	sdkTypes "github.com/raito-io/sdk-go/types"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/expression"
+)
+
+// defaultWhatRuleDoTypes are the data object types a what rule applies on if the grant does not define them.
+var defaultWhatRuleDoTypes = []string{"table", "view"}
+
+// toAbacExpression converts a validated expression into an ABAC expression. Each comparison is converted by the comparison function.
+func toAbacExpression[T base.Comparison](rule *base.BinaryExpression[T], comparison func(c T) *sdkTypes.AbacComparisonExpressionComparisonInput) sdkTypes.AbacComparisonExpressionInput {
+	switch {
+	case rule.Literal != nil:
+		return sdkTypes.AbacComparisonExpressionInput{Literal: utils.Ptr(*rule.Literal)}
+	case rule.Aggregator != nil:
+		operator := sdkTypes.AbacComparisonExpressionAggregatorOperatorAnd
+		if rule.Aggregator.Operator == base.AggregatorOperatorOr {
+			operator = sdkTypes.AbacComparisonExpressionAggregatorOperatorOr
+		}
+
+		operands := make([]sdkTypes.AbacComparisonExpressionInput, 0, len(rule.Aggregator.Operands))
+		for i := range rule.Aggregator.Operands {
+			operands = append(operands, toAbacExpression(&rule.Aggregator.Operands[i], comparison))
+		}
+
+		return sdkTypes.AbacComparisonExpressionInput{Aggregator: &sdkTypes.AbacComparisonExpressionAggregatorInput{
+			Operator: operator,
+			Operands: operands,
+		}}
+	case rule.UnaryExpression != nil:
+		return sdkTypes.AbacComparisonExpressionInput{UnaryExpression: &sdkTypes.AbacComparisonExpressionUnaryExpressionInput{
+			Operator: sdkTypes.AbacComparisonExpressionUnaryOperatorNot,
+			Operand:  toAbacExpression(&rule.UnaryExpression.Operand, comparison),
+		}}
+	default:
+		return sdkTypes.AbacComparisonExpressionInput{Comparison: comparison(rule.Comparison)}
+	}
+}
+
+// tagRuleToAbacExpression converts a validated tag rule into the ABAC expression of a what rule.
+func tagRuleToAbacExpression(rule *expression.TagRule) sdkTypes.AbacComparisonExpressionInput {
+	return toAbacExpression(rule, func(c *expression.TagComparison) *sdkTypes.AbacComparisonExpressionComparisonInput {
+		return &sdkTypes.AbacComparisonExpressionComparisonInput{
+			Operator:    sdkTypes.AbacComparisonExpressionComparisonOperatorHastag,
+			LeftOperand: c.Key,
+			RightOperand: sdkTypes.AbacComparisonExpressionOperandInput{
+				Literal: &sdkTypes.AbacComparisonExpressionLiteralInput{String: utils.Ptr(c.Value)},
+			},
+		}
+	})
+}
+
+// attributeRuleToAbacExpression converts a validated user attribute rule into the ABAC expression of a who rule.
+func attributeRuleToAbacExpression(rule *expression.AttributeRule) sdkTypes.AbacComparisonExpressionInput {
+	return toAbacExpression(rule, func(c *expression.AttributeComparison) *sdkTypes.AbacComparisonExpressionComparisonInput {
+		return &sdkTypes.AbacComparisonExpressionComparisonInput{
+			Operator:    abacComparisonOperator(c.Operator),
+			LeftOperand: c.Attribute,
+			RightOperand: sdkTypes.AbacComparisonExpressionOperandInput{
+				Literal: &sdkTypes.AbacComparisonExpressionLiteralInput{
+					Bool:   c.Value.Bool,
+					Int:    c.Value.Int,
+					Float:  c.Value.Float,
+					String: c.Value.Str,
+				},
+			},
+		}
+	})
+}
+
+func abacComparisonOperator(operator datacomparison.ComparisonOperator) sdkTypes.AbacComparisonExpressionComparisonOperator {
+	switch operator {
+	case datacomparison.ComparisonOperatorNotEqual:
+		return sdkTypes.AbacComparisonExpressionComparisonOperatorNotequals
+	case datacomparison.ComparisonOperatorLessThan:
+		return sdkTypes.AbacComparisonExpressionComparisonOperatorLessthan
+	case datacomparison.ComparisonOperatorLessThanOrEqual:
+		return sdkTypes.AbacComparisonExpressionComparisonOperatorLessthanorequal
+	case datacomparison.ComparisonOperatorGreaterThan:
+		return sdkTypes.AbacComparisonExpressionComparisonOperatorGreaterthan
+	case datacomparison.ComparisonOperatorGreaterThanOrEqual:
+		return sdkTypes.AbacComparisonExpressionComparisonOperatorGreaterthanorequal
+	case datacomparison.ComparisonOperatorEqual:
+		return sdkTypes.AbacComparisonExpressionComparisonOperatorEquals
+	default:
+		return sdkTypes.AbacComparisonExpressionComparisonOperatorEquals
+	}
+}
@@ -0,0 +1,91 @@
+package resource_provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/policy"
+)
+
+// exposureGrants returns, per unique id, the grants of the exposures that depend on the node. Grant templates are expanded on the exposure,
+// and a grant without owners is owned by the owner of the exposure. Exposures only support grants.
+func (s *DbtService) exposureGrants(manifestData *manifest.Manifest, templates *policy.Templates) (map[string][]manifest.Grant, error) {
+	result := make(map[string][]manifest.Grant)
+
+	var err error
+
+	for _, exposure := range manifestData.OrderedExposures() {
+		if len(exposure.Meta.Raito.Filter) > 0 || exposure.Meta.Raito.Mask != nil {
+			err = multierror.Append(err, fmt.Errorf("exposure %s: only grants are supported on exposures", exposure.UniqueId))
+
+			continue
+		}
+
+		if len(exposure.Meta.Raito.Grant) == 0 {
+			continue
+		}
+
+		grants, templateErr := templates.ExpandGrants(&manifest.Node{UniqueId: exposure.UniqueId, Name: exposure.Name, Meta: exposure.Meta})
+		if templateErr != nil {
+			err = multierror.Append(err, templateErr)
+
+			continue
+		}
+
+		for i := range grants {
+			if len(grants[i].Owners) == 0 && exposure.Owner.Email != "" {
+				grants[i].Owners = []string{exposure.Owner.Email}
+			}
+		}
+
+		for _, uniqueId := range exposure.DependsOn.Nodes {
+			s.logger.Debug(fmt.Sprintf("Grants of exposure %s cover %s", exposure.UniqueId, uniqueId))
+
+			result[uniqueId] = append(result[uniqueId], grants...)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// warnUngrantedDependencies logs a warning for every dependency of an exposure with grants that is not granted,
+// because it is not a node of the manifest (e.g. a source) or because it is not selected.
+func (s *DbtService) warnUngrantedDependencies(manifestData *manifest.Manifest, selected map[string]struct{}) {
+	for _, exposure := range manifestData.OrderedExposures() {
+		if len(exposure.Meta.Raito.Grant) == 0 {
+			continue
+		}
+
+		for _, uniqueId := range exposure.DependsOn.Nodes {
+			if _, found := selected[uniqueId]; found {
+				continue
+			}
+
+			if _, found := manifestData.Node(uniqueId); !found {
+				s.logger.Warn(fmt.Sprintf("Grants of exposure %s do not cover %s: only models, seeds and snapshots can be granted", exposure.UniqueId, uniqueId))
+
+				continue
+			}
+
+			s.logger.Warn(fmt.Sprintf("Grants of exposure %s do not cover %s: the node is not selected", exposure.UniqueId, uniqueId))
+		}
+	}
+}
+
+// withGrants returns a copy of the node with the grants added to its raito meta.
+func withGrants(node *manifest.Node, grants []manifest.Grant) *manifest.Node {
+	if len(grants) == 0 {
+		return node
+	}
+
+	result := *node
+	result.Meta.Raito.Grant = append(append([]manifest.Grant(nil), node.Meta.Raito.Grant...), grants...)
+
+	return &result
+}
@@ -0,0 +1,221 @@
+package resource_provider
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/raito-io/bexpression/utils"
+	"github.com/raito-io/golang-set/set"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+// propagateMasks adds the masks with `propagate: true` to the columns with the same name (case-insensitive) of the selected nodes downstream,
+// following the depends_on relations of the manifest. Columns that define another mask keep their mask, and the mask is not propagated beyond them.
+// A mask is only propagated through nodes that have a column with the same name. Nodes that are not selected are not masked, but the mask is
+// propagated through them. Propagated masks record the column they are propagated from. The nodes themselves are not modified.
+func (s *DbtService) propagateMasks(manifestData *manifest.Manifest, nodes []*manifest.Node) []*manifest.Node {
+	p := maskPropagation{
+		manifestData: manifestData,
+		nodes:        append([]*manifest.Node(nil), nodes...),
+		idx:          make(map[string]int, len(nodes)),
+		copied:       set.NewSet[string](),
+		logger:       s.logger,
+	}
+
+	for i, node := range p.nodes {
+		p.idx[node.UniqueId] = i
+	}
+
+	for i := range p.nodes {
+		node := p.nodes[i]
+
+		for _, key := range sortedColumnKeys(node) {
+			column := node.Columns[key]
+			if column.Meta.Raito.Mask != nil && column.Meta.Raito.Mask.Propagate && column.Meta.Raito.Mask.PropagatedFrom == "" {
+				p.propagate(node, columnName(key, &column), column.Meta.Raito.Mask)
+			}
+		}
+	}
+
+	return p.nodes
+}
+
+type maskPropagation struct {
+	manifestData *manifest.Manifest
+	nodes        []*manifest.Node
+	idx          map[string]int
+	copied       set.Set[string]
+	logger       hclog.Logger
+}
+
+func (p *maskPropagation) propagate(source *manifest.Node, column string, mask *manifest.Mask) {
+	visited := set.NewSet[string](source.UniqueId)
+	current := []string{source.UniqueId}
+
+	for len(current) > 0 {
+		var next []string
+
+		for _, uniqueId := range current {
+			for _, child := range p.manifestData.Children(uniqueId) {
+				if visited.Contains(child.UniqueId) {
+					continue
+				}
+
+				visited.Add(child.UniqueId)
+
+				if p.propagateToChild(source, column, mask, child) {
+					next = append(next, child.UniqueId)
+				}
+			}
+		}
+
+		current = next
+	}
+}
+
+// propagateToChild adds the mask to the column of the child and returns true if the mask should be propagated further downstream.
+func (p *maskPropagation) propagateToChild(source *manifest.Node, column string, mask *manifest.Mask, child *manifest.Node) bool {
+	childId := child.UniqueId
+
+	i, found := p.idx[childId]
+	if !found {
+		return p.propagateThrough(child, column, mask)
+	}
+
+	key, found := findColumnKey(p.nodes[i], column)
+	if !found {
+		return false
+	}
+
+	existing := p.nodes[i].Columns[key].Meta.Raito.Mask
+
+	switch {
+	case existing == nil:
+		if !p.copied.Contains(childId) {
+			copiedChild := *p.nodes[i]
+			copiedChild.Columns = maps.Clone(copiedChild.Columns)
+
+			p.nodes[i] = &copiedChild
+			p.copied.Add(childId)
+		}
+
+		childColumn := p.nodes[i].Columns[key]
+		propagated := *mask
+		propagated.PropagatedFrom = fmt.Sprintf("%s.%s", source.UniqueId, column)
+		childColumn.Meta.Raito.Mask = &propagated
+		p.nodes[i].Columns[key] = childColumn
+
+		p.logger.Info(fmt.Sprintf("Propagate mask %s from column %s of %s to column %s of %s", mask.Name, column, source.UniqueId, columnName(key, &childColumn), childId))
+
+		return true
+	case existing.Name == mask.Name:
+		return true
+	default:
+		childColumn := p.nodes[i].Columns[key]
+
+		p.logger.Info(fmt.Sprintf("Mask %s of column %s of %s is not propagated to column %s of %s, which defines mask %s", mask.Name, column, source.UniqueId, columnName(key, &childColumn), childId, existing.Name))
+
+		return false
+	}
+}
+
+// propagateThrough returns true if the mask should be propagated further downstream of a node that is not selected.
+// The node is not masked, but the mask is propagated through it as long as it has a column with the same name that does not define another mask.
+func (p *maskPropagation) propagateThrough(node *manifest.Node, column string, mask *manifest.Mask) bool {
+	key, found := findColumnKey(node, column)
+	if !found {
+		return false
+	}
+
+	existing := node.Columns[key].Meta.Raito.Mask
+	if existing != nil && existing.Name != mask.Name {
+		return false
+	}
+
+	p.logger.Debug(fmt.Sprintf("Propagate mask %s through %s, which is not selected", mask.Name, node.UniqueId))
+
+	return true
+}
+
+// logPropagatedMasks logs, per mask, the columns the mask is propagated to and the column it is propagated from, before the masks are synced.
+func (s *DbtService) logPropagatedMasks(masks map[string]*AccessProviderInput) {
+	names := make([]string, 0, len(masks))
+	for name := range masks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		propagated := masks[name].Propagated
+
+		columns := make([]string, 0, len(propagated))
+		for column := range propagated {
+			columns = append(columns, column)
+		}
+
+		sort.Strings(columns)
+
+		for _, column := range columns {
+			s.logger.Info(fmt.Sprintf("mask %s on column %s: propagated from %s", name, column, propagated[column]))
+		}
+	}
+}
+
+// describePropagatedMasks sets the description of the masks that are propagated to columns, so the column each mask is propagated from
+// shows in Raito Cloud, e.g. `db.sales.customer_emails.email: propagated from model.shop.customers.email`.
+func describePropagatedMasks(masks map[string]*AccessProviderInput) {
+	for _, mask := range masks {
+		if len(mask.Propagated) == 0 {
+			continue
+		}
+
+		columns := make([]string, 0, len(mask.Propagated))
+		for column := range mask.Propagated {
+			columns = append(columns, column)
+		}
+
+		sort.Strings(columns)
+
+		lines := make([]string, 0, len(columns))
+		for _, column := range columns {
+			lines = append(lines, fmt.Sprintf("%s: propagated from %s", column, mask.Propagated[column]))
+		}
+
+		mask.Input.Description = utils.Ptr(strings.Join(lines, "\n"))
+	}
+}
+
+func columnName(key string, column *manifest.Column) string {
+	if column.Name != "" {
+		return column.Name
+	}
+
+	return key
+}
+
+func findColumnKey(node *manifest.Node, name string) (string, bool) {
+	for _, key := range sortedColumnKeys(node) {
+		column := node.Columns[key]
+		if strings.EqualFold(columnName(key, &column), name) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+func sortedColumnKeys(node *manifest.Node) []string {
+	keys := make([]string, 0, len(node.Columns))
+	for key := range node.Columns {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
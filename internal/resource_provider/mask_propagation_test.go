@@ -0,0 +1,110 @@
+package resource_provider
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+)
+
+func TestDbtService_loadAccessProvidersFromManifest_MaskPropagation(t *testing.T) {
+	model := func(name string, dependsOn []string, columns ...manifest.Column) manifest.Node {
+		node := manifest.Node{Database: "db", Schema: "sales", Name: name, ResourceType: "model", DependsOn: manifest.NodeDependsOn{Nodes: dependsOn}, Columns: map[string]manifest.Column{}}
+		for _, column := range columns {
+			node.Columns[column.Name] = column
+		}
+
+		return node
+	}
+
+	column := func(name string, mask *manifest.Mask) manifest.Column {
+		return manifest.Column{Name: name, Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: mask}}}
+	}
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.customers":       model("customers", nil, column("email", &manifest.Mask{Name: "email_mask", Propagate: true}), column("name", nil)),
+			"model.shop.mart_customers":  model("mart_customers", []string{"model.shop.customers"}, column("EMAIL", nil), column("name", nil)),
+			"model.shop.customer_emails": model("customer_emails", []string{"model.shop.mart_customers"}, column("email", nil)),
+			"model.shop.customers_eu":    model("customers_eu", []string{"model.shop.customers"}, column("email", &manifest.Mask{Name: "eu_email_mask"})),
+			"model.shop.eu_report":       model("eu_report", []string{"model.shop.customers_eu"}, column("email", nil)),
+			"model.shop.customer_counts": model("customer_counts", []string{"model.shop.customers"}, column("count", nil)),
+			"model.shop.count_report":    model("count_report", []string{"model.shop.customer_counts"}, column("email", nil)),
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	s, _, _, _ := createDbtService(t, "dsId1")
+
+	_, _, _, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+		Namer:           naming.NewNamer("", naming.CasingAdapter),
+		CrossTableMasks: true,
+	})
+	require.NoError(t, err)
+
+	maskedColumns := func(mask *AccessProviderInput) []string {
+		var result []string
+		for _, whatDo := range mask.Input.WhatDataObjects {
+			result = append(result, whatDo.DataObjectByName[0].Fullname)
+		}
+
+		sort.Strings(result)
+
+		return result
+	}
+
+	require.Len(t, masks, 2)
+	assert.Equal(t, []string{"db.sales.customer_emails.email", "db.sales.customers.email", "db.sales.mart_customers.EMAIL"}, maskedColumns(masks["email_mask"]))
+	assert.Equal(t, []string{"db.sales.customers_eu.email"}, maskedColumns(masks["eu_email_mask"]))
+
+	assert.Equal(t, map[string]string{
+		"db.sales.mart_customers.EMAIL":  "model.shop.customers.email",
+		"db.sales.customer_emails.email": "model.shop.customers.email",
+	}, masks["email_mask"].Propagated)
+	assert.Empty(t, masks["eu_email_mask"].Propagated)
+
+	require.NotNil(t, masks["email_mask"].Input.Description)
+	assert.Equal(t, "db.sales.customer_emails.email: propagated from model.shop.customers.email\ndb.sales.mart_customers.EMAIL: propagated from model.shop.customers.email", *masks["email_mask"].Input.Description)
+	assert.Nil(t, masks["eu_email_mask"].Input.Description)
+
+	martCustomers, _ := manifestData.Node("model.shop.mart_customers")
+	assert.Nil(t, martCustomers.Columns["EMAIL"].Meta.Raito.Mask)
+
+	t.Run("through unselected nodes", func(t *testing.T) {
+		selection, selectionErr := manifest.ParseSelection("", "mart_customers customers_eu")
+		require.NoError(t, selectionErr)
+
+		_, _, _, selectedMasks, loadErr := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer:           naming.NewNamer("", naming.CasingAdapter),
+			Selection:       selection,
+			CrossTableMasks: true,
+		})
+		require.NoError(t, loadErr)
+
+		require.Len(t, selectedMasks, 1)
+		assert.Equal(t, []string{"db.sales.customer_emails.email", "db.sales.customers.email"}, maskedColumns(selectedMasks["email_mask"]))
+		assert.Equal(t, map[string]string{"db.sales.customer_emails.email": "model.shop.customers.email"}, selectedMasks["email_mask"].Propagated)
+	})
+
+	t.Run("split per table", func(t *testing.T) {
+		_, _, _, splitMasks, loadErr := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer: naming.NewNamer("", naming.CasingAdapter),
+		})
+		require.NoError(t, loadErr)
+
+		assert.Empty(t, splitMasks["email_mask-customers"].Propagated)
+		assert.Equal(t, map[string]string{"db.sales.mart_customers.EMAIL": "model.shop.customers.email"}, splitMasks["email_mask-mart_customers"].Propagated)
+
+		assert.Nil(t, splitMasks["email_mask-customers"].Input.Description)
+		require.NotNil(t, splitMasks["email_mask-mart_customers"].Input.Description)
+		assert.Equal(t, "db.sales.mart_customers.EMAIL: propagated from model.shop.customers.email", *splitMasks["email_mask-mart_customers"].Input.Description)
+	})
+}
@@ -0,0 +1,129 @@
+package resource_provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+)
+
+const (
+	DataTypeCategoryString    = "string"
+	DataTypeCategoryNumber    = "number"
+	DataTypeCategoryDate      = "date"
+	DataTypeCategoryTimestamp = "timestamp"
+	DataTypeCategoryBoolean   = "boolean"
+)
+
+// dataTypeCategories maps the (lower case) data types of the supported adapters on their category.
+var dataTypeCategories = map[string]string{
+	"string":                      DataTypeCategoryString,
+	"text":                        DataTypeCategoryString,
+	"varchar":                     DataTypeCategoryString,
+	"char":                        DataTypeCategoryString,
+	"character":                   DataTypeCategoryString,
+	"character varying":           DataTypeCategoryString,
+	"nvarchar":                    DataTypeCategoryString,
+	"nchar":                       DataTypeCategoryString,
+	"number":                      DataTypeCategoryNumber,
+	"numeric":                     DataTypeCategoryNumber,
+	"decimal":                     DataTypeCategoryNumber,
+	"int":                         DataTypeCategoryNumber,
+	"integer":                     DataTypeCategoryNumber,
+	"bigint":                      DataTypeCategoryNumber,
+	"smallint":                    DataTypeCategoryNumber,
+	"tinyint":                     DataTypeCategoryNumber,
+	"int64":                       DataTypeCategoryNumber,
+	"float":                       DataTypeCategoryNumber,
+	"float64":                     DataTypeCategoryNumber,
+	"double":                      DataTypeCategoryNumber,
+	"double precision":            DataTypeCategoryNumber,
+	"real":                        DataTypeCategoryNumber,
+	"bignumeric":                  DataTypeCategoryNumber,
+	"date":                        DataTypeCategoryDate,
+	"timestamp":                   DataTypeCategoryTimestamp,
+	"timestamp_ntz":               DataTypeCategoryTimestamp,
+	"timestamp_ltz":               DataTypeCategoryTimestamp,
+	"timestamp_tz":                DataTypeCategoryTimestamp,
+	"timestamptz":                 DataTypeCategoryTimestamp,
+	"datetime":                    DataTypeCategoryTimestamp,
+	"timestamp with time zone":    DataTypeCategoryTimestamp,
+	"timestamp without time zone": DataTypeCategoryTimestamp,
+	"boolean":                     DataTypeCategoryBoolean,
+	"bool":                        DataTypeCategoryBoolean,
+}
+
+// MaskTypeMapping maps data types, or data type categories, on the mask type that is used if a mask does not define a type.
+type MaskTypeMapping map[string]string
+
+// ParseMaskTypeMapping parses a comma separated list of `<data type or category>=<mask type>` pairs, e.g. `string=SHA256,date=NULL`.
+func ParseMaskTypeMapping(value string) (MaskTypeMapping, error) {
+	mapping, err := naming.ParseMapping(value)
+	if err != nil {
+		return nil, fmt.Errorf("mask type mapping: %w", err)
+	}
+
+	result := make(MaskTypeMapping, len(mapping))
+	for dataType, maskType := range mapping {
+		result[normaliseDataType(dataType)] = maskType
+	}
+
+	return result, nil
+}
+
+// MaskType returns the mask type of the data type. An exact data type takes precedence over its category.
+func (m MaskTypeMapping) MaskType(dataType string) (string, bool) {
+	dataType = normaliseDataType(dataType)
+
+	if maskType, found := m[dataType]; found {
+		return maskType, true
+	}
+
+	category, found := dataTypeCategories[dataType]
+	if !found {
+		return "", false
+	}
+
+	maskType, found := m[category]
+
+	return maskType, found
+}
+
+// IncompatibleMaskType returns an error if the mask type is only mapped on other data types than the data type of the column.
+// Mask types that are not part of the mapping are considered compatible.
+func (m MaskTypeMapping) IncompatibleMaskType(maskType string, dataType string) error {
+	dataType = normaliseDataType(dataType)
+	category := dataTypeCategories[dataType]
+
+	var mappedOn []string
+
+	for mappedType, mappedMaskType := range m {
+		if mappedMaskType != maskType {
+			continue
+		}
+
+		if mappedType == dataType || mappedType == category {
+			return nil
+		}
+
+		mappedOn = append(mappedOn, mappedType)
+	}
+
+	if len(mappedOn) == 0 {
+		return nil
+	}
+
+	sort.Strings(mappedOn)
+
+	return fmt.Errorf("mask type %s is used for %s columns, not for %s", maskType, strings.Join(mappedOn, ", "), dataType)
+}
+
+// normaliseDataType returns the lower case data type without size or precision, e.g. `VARCHAR(256)` becomes `varchar`.
+func normaliseDataType(dataType string) string {
+	if idx := strings.IndexAny(dataType, "(<"); idx >= 0 {
+		dataType = dataType[:idx]
+	}
+
+	return strings.ToLower(strings.TrimSpace(dataType))
+}
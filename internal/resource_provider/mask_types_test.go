@@ -0,0 +1,50 @@
+package resource_provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskTypeMapping(t *testing.T) {
+	mapping, err := ParseMaskTypeMapping("string=SHA256, date=NULL, timestamp=NULL, VARCHAR(2)=MASK_COUNTRY")
+	require.NoError(t, err)
+
+	t.Run("MaskType", func(t *testing.T) {
+		tests := []struct {
+			dataType  string
+			want      string
+			wantFound bool
+		}{
+			{dataType: "text", want: "SHA256", wantFound: true},
+			{dataType: "VARCHAR(256)", want: "MASK_COUNTRY", wantFound: true},
+			{dataType: "character varying(64)", want: "SHA256", wantFound: true},
+			{dataType: "DATE", want: "NULL", wantFound: true},
+			{dataType: "TIMESTAMP_NTZ(9)", want: "NULL", wantFound: true},
+			{dataType: "NUMBER(38,0)", wantFound: false},
+			{dataType: "geography", wantFound: false},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.dataType, func(t *testing.T) {
+				got, found := mapping.MaskType(tt.dataType)
+				assert.Equal(t, tt.wantFound, found)
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("IncompatibleMaskType", func(t *testing.T) {
+		require.NoError(t, mapping.IncompatibleMaskType("SHA256", "string"))
+		require.NoError(t, mapping.IncompatibleMaskType("NULL", "timestamp_ltz"))
+		require.NoError(t, mapping.IncompatibleMaskType("RANDOM", "date"))
+		assert.EqualError(t, mapping.IncompatibleMaskType("SHA256", "DATE"), "mask type SHA256 is used for string columns, not for date")
+		assert.EqualError(t, mapping.IncompatibleMaskType("NULL", "int"), "mask type NULL is used for date, timestamp columns, not for int")
+	})
+
+	t.Run("invalid mapping", func(t *testing.T) {
+		_, err := ParseMaskTypeMapping("string")
+		assert.ErrorContains(t, err, "invalid mapping")
+	})
+}
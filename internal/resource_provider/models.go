@@ -3,6 +3,10 @@ package resource_provider
 import (
 	"github.com/raito-io/golang-set/set"
 	sdkTypes "github.com/raito-io/sdk-go/types"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+	"github.com/raito-io/cli-plugin-dbt/internal/policy"
 )
 
 type ResourceStatus int
@@ -17,4 +21,47 @@ const (
 type AccessProviderInput struct {
 	Input  sdkTypes.AccessProviderInput
 	Owners set.Set[string]
+
+	// Propagated maps the fullnames of the columns a mask is propagated to on the column (`<unique id>.<column>`) the mask is propagated from.
+	Propagated map[string]string
+}
+
+type projectAccessProviders struct {
+	source  string
+	grants  map[string]*AccessProviderInput
+	filters map[string]*AccessProviderInput
+	masks   map[string]*AccessProviderInput
+}
+
+// SyncOptions defines which nodes of the manifest are synced and how they are translated into access providers.
+type SyncOptions struct {
+	Namer     *naming.Namer
+	Selection *manifest.Selection
+
+	// LatestVersionOnly only applies the policies of versioned models on the latest version.
+	LatestVersionOnly bool
+
+	// PolicyRuleVariables are the variables that can be used in the policy rules of filters as `{{ var.<name> }}`.
+	PolicyRuleVariables map[string]string
+
+	// Target describes the dbt target the manifest is built with. Its values can be used in the policy rules of filters as `{{ target.<key> }}`.
+	Target map[string]string
+
+	// Templates are the reusable policies the nodes can refer to.
+	Templates *policy.Templates
+
+	// CrossTableMasks allows a mask to be applied on columns of multiple tables. Otherwise, such masks are split per table.
+	CrossTableMasks bool
+
+	// MaskTypes defines the type of the masks that do not define a type, based on the data type of the column.
+	MaskTypes MaskTypeMapping
+
+	// Catalog provides the data types of the columns that do not document a data_type.
+	Catalog *manifest.Catalog
+
+	// Rules add policies to the nodes and columns based on their tags and meta.
+	Rules *policy.Rules
+
+	// Policies are the grants, filters and masks declared in the policies file.
+	Policies *policy.Policies
 }
@@ -0,0 +1,127 @@
+package resource_provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+var policyRuleVariableRegex = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*}}`)
+
+// policyRuleVariables returns the variables that can be used in the policy rule of a filter defined on the node:
+//   - this.database, this.schema, this.name and this.identifier: the relation of the node
+//   - this.column.<column>: the name of a column defined on the node
+//   - target.<key>: the dbt target defined by the dbt-target parameter, e.g. target.name or target.schema
+//   - meta.<key>: the meta values of the node
+//   - var.<name>: the variables defined by the policy-rule-variables parameter
+func policyRuleVariables(node *manifest.Node, variables map[string]string, target map[string]string) map[string]interface{} {
+	identifier := node.Alias
+	if identifier == "" {
+		identifier = node.Name
+	}
+
+	columns := make(map[string]interface{}, len(node.Columns))
+
+	for key, column := range node.Columns {
+		name := column.Name
+		if name == "" {
+			name = key
+		}
+
+		columns[key] = name
+		columns[strings.ToLower(name)] = name
+	}
+
+	vars := make(map[string]interface{}, len(variables))
+	for key, value := range variables {
+		vars[key] = value
+	}
+
+	targetVars := make(map[string]interface{}, len(target))
+	for key, value := range target {
+		targetVars[key] = value
+	}
+
+	return map[string]interface{}{
+		"this": map[string]interface{}{
+			"database":   node.Database,
+			"schema":     node.Schema,
+			"name":       node.Name,
+			"identifier": identifier,
+			"column":     columns,
+		},
+		"target": targetVars,
+		"meta":   node.Config.Meta,
+		"var":    vars,
+	}
+}
+
+// renderPolicyRule replaces the `{{ <variable> }}` placeholders of the policy rule by the value of the variables.
+// All undefined variables are reported.
+func renderPolicyRule(policyRule string, variables map[string]interface{}) (string, error) {
+	var err error
+
+	result := policyRuleVariableRegex.ReplaceAllStringFunc(policyRule, func(placeholder string) string {
+		name := policyRuleVariableRegex.FindStringSubmatch(placeholder)[1]
+
+		value, resolveErr := resolvePolicyRuleVariable(name, variables)
+		if resolveErr != nil {
+			err = multierror.Append(err, resolveErr)
+
+			return placeholder
+		}
+
+		return value
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func resolvePolicyRuleVariable(name string, variables map[string]interface{}) (string, error) {
+	var value interface{} = variables
+
+	for _, part := range strings.Split(name, ".") {
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("undefined variable %q", name)
+		}
+
+		value, ok = values[part]
+		if !ok {
+			if available := keys(values); len(available) > 0 {
+				return "", fmt.Errorf("undefined variable %q: %q is not one of %s", name, part, strings.Join(available, ", "))
+			}
+
+			return "", fmt.Errorf("undefined variable %q", name)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("variable %q is not a single value", name)
+	case nil:
+		return "", fmt.Errorf("variable %q is null", name)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+func keys(values map[string]interface{}) []string {
+	result := make([]string, 0, len(values))
+	for key := range values {
+		result = append(result, key)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,96 @@
+package resource_provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+)
+
+func Test_renderPolicyRule(t *testing.T) {
+	node := &manifest.Node{
+		Database: "analytics",
+		Schema:   "sales",
+		Name:     "orders",
+		Alias:    "fct_orders",
+		Columns: map[string]manifest.Column{
+			"region":  {Name: "Region"},
+			"country": {},
+		},
+		Config: manifest.NodeConfig{Meta: map[string]interface{}{
+			"owner":  "sales-team",
+			"limits": map[string]interface{}{"max_amount": float64(1000)},
+		}},
+	}
+
+	variables := policyRuleVariables(node, map[string]string{"regions": "'EU', 'US'"}, map[string]string{"name": "prod", "schema": "dbt_prod"})
+
+	tests := []struct {
+		name       string
+		policyRule string
+		want       string
+		wantErr    []string
+	}{
+		{
+			name:       "without variables",
+			policyRule: "region = 'EU'",
+			want:       "region = 'EU'",
+		},
+		{
+			name:       "columns, relation and target",
+			policyRule: "{{ this.column.region }} IN ({{var.regions}}) AND {{ this.column.country }} IS NOT NULL AND {{ target.schema }}.{{ this.identifier }}.id > 0",
+			want:       "Region IN ('EU', 'US') AND country IS NOT NULL AND dbt_prod.fct_orders.id > 0",
+		},
+		{
+			name:       "meta values",
+			policyRule: "owner = '{{ meta.owner }}' AND amount < {{ meta.limits.max_amount }}",
+			want:       "owner = 'sales-team' AND amount < 1000",
+		},
+		{
+			name:       "undefined variables",
+			policyRule: "{{ this.column.city }} = 'Brussels' AND {{ var.country }} = country AND {{ meta.owner.name }} IS NULL AND '{{ target.database }}' = 'analytics'",
+			wantErr: []string{
+				`undefined variable "target.database": "database" is not one of name, schema`,
+				`undefined variable "this.column.city": "city" is not one of country, region`,
+				`undefined variable "var.country": "country" is not one of regions`,
+				`undefined variable "meta.owner.name"`,
+			},
+		},
+		{
+			name:       "not a single value",
+			policyRule: "{{ this.column }} IS NULL",
+			wantErr:    []string{`variable "this.column" is not a single value`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderPolicyRule(tt.policyRule, variables)
+
+			if len(tt.wantErr) > 0 {
+				require.Error(t, err)
+
+				for _, wantErr := range tt.wantErr {
+					assert.ErrorContains(t, err, wantErr)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("policy-rule-variables parameter", func(t *testing.T) {
+		parameterVariables, err := naming.ParseMapping(`regions="'EU','US'",country='BE'`)
+		require.NoError(t, err)
+
+		got, err := renderPolicyRule("{{ this.column.region }} IN ({{ var.regions }}) AND country = {{ var.country }}", policyRuleVariables(node, parameterVariables, nil))
+		require.NoError(t, err)
+		assert.Equal(t, "Region IN ('EU','US') AND country = 'BE'", got)
+	})
+}
@@ -2,14 +2,16 @@ package resource_provider
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
+	"github.com/raito-io/bexpression"
 	"github.com/raito-io/bexpression/utils"
 	"github.com/raito-io/cli/base/resource_provider"
 	"github.com/raito-io/golang-set/set"
@@ -18,7 +20,10 @@ import (
 	"github.com/raito-io/sdk-go/types/models"
 
 	"github.com/raito-io/cli-plugin-dbt/internal/array"
+	"github.com/raito-io/cli-plugin-dbt/internal/expression"
 	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+	"github.com/raito-io/cli-plugin-dbt/internal/policy"
 	"github.com/raito-io/cli-plugin-dbt/internal/workerpool"
 )
 
@@ -69,23 +74,42 @@ func NewDbtService(config *resource_provider.UpdateResourceInput, accessProvider
 	}
 }
 
-func (s *DbtService) RunDbt(ctx context.Context, dbtFile string, fullnamePrefix string) (uint32, uint32, uint32, uint32, error) {
-	manifestData, err := s.loadDbtFile(dbtFile)
+func (s *DbtService) RunDbt(ctx context.Context, dbtFile string, options *SyncOptions, loadOptions ...func(options *manifest.LoadOptions)) (uint32, uint32, uint32, uint32, error) {
+	mesh, err := manifest.LoadMesh(ctx, s.manifestParser, dbtFile, loadOptions...)
 	if err != nil {
 		return 0, 0, 0, 0, fmt.Errorf("load file %s: %w", dbtFile, err)
 	}
 
-	source, grants, filters, masks, err := s.loadAccessProvidersFromManifest(ctx, manifestData, fullnamePrefix)
+	projects, err := s.loadAccessProvidersFromMesh(ctx, mesh, options)
 	if err != nil {
 		return 0, 0, 0, 0, fmt.Errorf("load access providers from manifest: %w", err)
 	}
 
-	grantIds, filterIds, maskIds, apsToRemove, err := s.loadExistingAps(ctx, source, grants, filters, masks)
-	if err != nil {
-		return 0, 0, 0, 0, err
+	var addedResources, updatedResources, deletedResources, failures uint32
+
+	for _, project := range projects {
+		grantIds, filterIds, maskIds, apsToRemove, loadErr := s.loadExistingAps(ctx, project.source, project.grants, project.filters, project.masks)
+		if loadErr != nil {
+			err = multierror.Append(err, fmt.Errorf("source %s: %w", project.source, loadErr))
+
+			continue
+		}
+
+		s.logPropagatedMasks(project.masks)
+
+		added, updated, deleted, failed, updateErr := s.createAndUpdateAccessProviders(ctx, project.grants, grantIds, project.masks, maskIds, project.filters, filterIds, apsToRemove)
+
+		addedResources += added
+		updatedResources += updated
+		deletedResources += deleted
+		failures += failed
+
+		if updateErr != nil {
+			err = multierror.Append(err, fmt.Errorf("source %s: %w", project.source, updateErr))
+		}
 	}
 
-	return s.createAndUpdateAccessProviders(ctx, grants, grantIds, masks, maskIds, filters, filterIds, apsToRemove)
+	return addedResources, updatedResources, deletedResources, failures, err
 }
 
 func (s *DbtService) createAndUpdateAccessProviders(ctx context.Context, grants map[string]*AccessProviderInput, grantIds map[string]string, masks map[string]*AccessProviderInput, maskIds map[string]string, filters map[string]*AccessProviderInput, filterIds map[string]string, apsToRemove set.Set[string]) (uint32, uint32, uint32, uint32, error) {
@@ -288,24 +312,79 @@ func (s *DbtService) loadExistingAps(ctx context.Context, source string, grants
 	return grantIds, filterIds, maskIds, apsToRemove, nil
 }
 
-func (s *DbtService) loadDbtFile(dbtFilePath string) (*manifest.Manifest, error) {
-	jsonBytes, err := os.ReadFile(dbtFilePath)
+// loadAccessProvidersFromMesh loads the access providers of each project in the mesh.
+// Each project is synced under its own source, access providers with the same name in different projects are rejected.
+func (s *DbtService) loadAccessProvidersFromMesh(ctx context.Context, mesh *manifest.Mesh, options *SyncOptions) ([]*projectAccessProviders, error) {
+	projects := make([]*projectAccessProviders, 0, len(mesh.Manifests))
+
+	err := options.Policies.Validate(mesh)
 	if err != nil {
-		return nil, fmt.Errorf("reading dbt file: %w", err)
+		return nil, fmt.Errorf("policies: %w", err)
 	}
 
-	var result manifest.Manifest
+	for _, manifestData := range mesh.Manifests {
+		source, grants, filters, masks, loadErr := s.loadAccessProvidersFromManifest(ctx, mesh, manifestData, options)
+		if loadErr != nil {
+			err = multierror.Append(err, fmt.Errorf("project %s: %w", manifestData.Metadata.ProjectName, loadErr))
+
+			continue
+		}
+
+		projects = append(projects, &projectAccessProviders{
+			source:  source,
+			grants:  grants,
+			filters: filters,
+			masks:   masks,
+		})
+	}
 
-	err = json.Unmarshal(jsonBytes, &result)
 	if err != nil {
-		return nil, fmt.Errorf("parsing dbt file: %w", err)
+		return nil, err
+	}
+
+	err = checkDuplicatedAccessProviders(projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func checkDuplicatedAccessProviders(projects []*projectAccessProviders) error {
+	var err error
+
+	grantSources := make(map[string]string)
+	filterSources := make(map[string]string)
+	maskSources := make(map[string]string)
+
+	check := func(apType string, aps map[string]*AccessProviderInput, source string, sources map[string]string) {
+		names := make([]string, 0, len(aps))
+		for name := range aps {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			if otherSource, found := sources[name]; found {
+				err = multierror.Append(err, fmt.Errorf("%s %q is defined in multiple projects (%s and %s)", apType, name, otherSource, source))
+			} else {
+				sources[name] = source
+			}
+		}
+	}
+
+	for _, project := range projects {
+		check("grant", project.grants, project.source, grantSources)
+		check("filter", project.filters, project.source, filterSources)
+		check("mask", project.masks, project.source, maskSources)
 	}
 
-	return &result, nil
+	return err
 }
 
-func (s *DbtService) loadAccessProvidersFromManifest(ctx context.Context, manifestData *manifest.Manifest, fullnamePrefix string) (string, map[string]*AccessProviderInput, map[string]*AccessProviderInput, map[string]*AccessProviderInput, error) {
-	source := _source(manifestData.Metadata.ProjectName)
+func (s *DbtService) loadAccessProvidersFromManifest(ctx context.Context, mesh *manifest.Mesh, manifestData *manifest.Manifest, options *SyncOptions) (string, map[string]*AccessProviderInput, map[string]*AccessProviderInput, map[string]*AccessProviderInput, error) {
+	source := _source(manifestData.Metadata.ProjectName, options.Selection)
 
 	grants := make(map[string]*AccessProviderInput)
 	filters := make(map[string]*AccessProviderInput)
@@ -328,34 +407,131 @@ func (s *DbtService) loadAccessProvidersFromManifest(ctx context.Context, manife
 		},
 	}
 
-	supportedResourceTypes := set.NewSet("model", "seed", "snapshot")
+	maskTables := make(map[string][]maskTable)
+
+	nodes, err := manifestData.SelectDataObjectNodes(options.Selection)
+	if err != nil {
+		return "", nil, nil, nil, fmt.Errorf("select nodes: %w", err)
+	}
+
+	declaredPolicies, err := options.Policies.Resolve(manifestData)
+	if err != nil {
+		return "", nil, nil, nil, fmt.Errorf("resolve policies: %w", err)
+	}
+
+	exposureGrants, err := s.exposureGrants(manifestData, options.Templates)
+	if err != nil {
+		return "", nil, nil, nil, fmt.Errorf("exposures: %w", err)
+	}
+
+	selected := make(map[string]struct{}, len(nodes))
+	selectedNodes := make([]*manifest.Node, 0, len(nodes))
+
+	for _, node := range nodes {
+		if options.LatestVersionOnly && !node.IsLatestVersion() {
+			node = withoutRaitoMeta(node)
+		}
+
+		var conflicts []policy.Conflict
+
+		node, conflicts = declaredPolicies.Apply(node)
+		for _, conflict := range conflicts {
+			s.logger.Warn(conflict.String())
+		}
+
+		node = options.Rules.Apply(node)
+		node = withGrants(node, exposureGrants[node.UniqueId])
+
+		selected[node.UniqueId] = struct{}{}
+		selectedNodes = append(selectedNodes, node)
+	}
+
+	s.warnUngrantedDependencies(manifestData, selected)
+
+	selectedNodes = s.propagateMasks(manifestData, selectedNodes)
+
+	resolveRef := func(ref string) (string, error) {
+		refNode, refErr := mesh.ResolveRef(manifestData, ref)
+		if refErr != nil {
+			return "", refErr
+		}
+
+		refManifest := manifestData
+		if _, found := manifestData.Node(refNode.UniqueId); !found {
+			_, refManifest, _ = mesh.Node(refNode.UniqueId)
+		}
+
+		return options.Namer.DataObject(refManifest, refNode)
+	}
+
+	for _, node := range selectedNodes {
+		doName, nameErr := options.Namer.DataObject(manifestData, node)
+		if nameErr != nil {
+			err = multierror.Append(err, fmt.Errorf("data object name of %s: %w", node.UniqueId, nameErr))
 
-	for i := range manifestData.Nodes {
-		if !supportedResourceTypes.Contains(manifestData.Nodes[i].ResourceType) {
 			continue
 		}
 
-		databaseName := manifestData.Nodes[i].Database
-		schemaName := manifestData.Nodes[i].Schema
-		modelName := manifestData.Nodes[i].Name
-		doName := fmt.Sprintf("%s%s.%s.%s", fullnamePrefix, databaseName, schemaName, modelName)
+		scopeFullname := func(scope string) (string, error) {
+			switch scope {
+			case "":
+				return doName, nil
+			case manifest.GrantScopeSchema:
+				return options.Namer.Schema(manifestData, node)
+			case manifest.GrantScopeDatabase:
+				return options.Namer.Database(manifestData, node)
+			default:
+				return "", fmt.Errorf("unsupported scope %q: expected %s or %s", scope, manifest.GrantScopeSchema, manifest.GrantScopeDatabase)
+			}
+		}
+
+		columnFullname := func(column *manifest.Column) (string, error) {
+			return options.Namer.Column(manifestData, node, column)
+		}
+
+		columnName := func(column *manifest.Column) string {
+			return options.Namer.ColumnName(manifestData, column)
+		}
+
+		downstreamFullnames := func(depth int) ([]string, error) {
+			return downstreamDataObjects(manifestData, node, depth, selected, options.Namer)
+		}
 
-		gErr := s.parseGrants(ctx, manifestData, i, grants, source, defaultLocks, doName)
+		gErr := s.parseGrants(ctx, node, options.Templates, grants, source, defaultLocks, scopeFullname, resolveRef, downstreamFullnames)
 		if gErr != nil {
 			err = multierror.Append(err, fmt.Errorf("parse grants: %w", gErr))
 		}
 
-		fErr := s.parseFilters(ctx, manifestData, i, filters, source, doName, defaultLocks)
-		if fErr != nil {
-			err = multierror.Append(err, fmt.Errorf("parse filters: %w", fErr))
+		nodeFilters, templateErr := options.Templates.ExpandFilters(manifestData.Metadata.ProjectName, node)
+		if templateErr != nil {
+			err = multierror.Append(err, fmt.Errorf("parse filters: %w", templateErr))
+		} else {
+			fErr := s.parseFilters(ctx, node, nodeFilters, filters, source, doName, columnName, policyRuleVariables(node, options.PolicyRuleVariables, options.Target), defaultLocks)
+			if fErr != nil {
+				err = multierror.Append(err, fmt.Errorf("parse filters: %w", fErr))
+			}
 		}
 
-		mErr := s.parseMasks(ctx, manifestData, i, masks, doName, source, defaultLocks)
+		mErr := s.parseMasks(ctx, node, masks, maskTables, options.MaskTypes, options.Catalog, doName, columnFullname, source, defaultLocks)
 		if mErr != nil {
 			err = multierror.Append(err, fmt.Errorf("parse masks: %w", mErr))
 		}
 	}
 
+	if !options.CrossTableMasks {
+		splitErr := s.splitMasksPerTable(manifestData.Metadata.ProjectName, masks, maskTables)
+		if splitErr != nil {
+			err = multierror.Append(err, fmt.Errorf("split masks: %w", splitErr))
+		}
+	}
+
+	describePropagatedMasks(masks)
+
+	typeErr := resolveMaskTypes(masks, maskTables)
+	if typeErr != nil {
+		err = multierror.Append(err, fmt.Errorf("mask types: %w", typeErr))
+	}
+
 	if err != nil {
 		return source, nil, nil, nil, err
 	}
@@ -363,53 +539,34 @@ func (s *DbtService) loadAccessProvidersFromManifest(ctx context.Context, manife
 	return source, grants, filters, masks, nil
 }
 
-func (s *DbtService) parseMasks(ctx context.Context, manifestData *manifest.Manifest, i string, masks map[string]*AccessProviderInput, doName string, source string, defaultLocks []sdkTypes.AccessProviderLockDataInput) error {
+func (s *DbtService) parseMasks(ctx context.Context, node *manifest.Node, masks map[string]*AccessProviderInput, maskTables map[string][]maskTable, maskTypes MaskTypeMapping, catalog *manifest.Catalog, doName string, columnFullname func(column *manifest.Column) (string, error), source string, defaultLocks []sdkTypes.AccessProviderLockDataInput) error {
 	var err error
 
-	for columnIdx := range manifestData.Nodes[i].Columns {
-		column := manifestData.Nodes[i].Columns[columnIdx]
+	for _, columnIdx := range sortedColumnKeys(node) {
+		column := node.Columns[columnIdx]
 
 		if column.Meta.Raito.Mask == nil {
 			continue
 		}
 
-		if mask, found := masks[column.Meta.Raito.Mask.Name]; found {
-			if len(mask.Input.DataSources) > 0 && mask.Input.DataSources[0].Type != nil && column.Meta.Raito.Mask.Type != nil && *column.Meta.Raito.Mask.Type != *mask.Input.DataSources[0].Type {
-				err = multierror.Append(err, fmt.Errorf("mask %s already exists with different type", column.Meta.Raito.Mask.Name))
-
-				continue
-			}
-
-			isValid := true
+		columnName, nameErr := columnFullname(&column)
+		if nameErr != nil {
+			err = multierror.Append(err, fmt.Errorf("column name of %s.%s: %w", node.UniqueId, column.Name, nameErr))
 
-			for _, dos := range mask.Input.WhatDataObjects {
-				for _, do := range dos.DataObjectByName {
-					if !strings.HasPrefix(do.Fullname, doName) {
-						err = multierror.Append(err, fmt.Errorf("mask %s can not be applied on multiple tables", column.Meta.Raito.Mask.Name))
-						isValid = false
+			continue
+		}
 
-						break
-					}
-				}
+		maskType := s.maskType(node, columnIdx, &column, maskTypes, catalog)
 
-				if !isValid {
-					break
-				}
-			}
-
-			if !isValid {
-				continue
-			}
-		} else {
+		if _, found := masks[column.Meta.Raito.Mask.Name]; !found {
 			masks[column.Meta.Raito.Mask.Name] = &AccessProviderInput{
 				Input: sdkTypes.AccessProviderInput{
-					Name:     &manifestData.Nodes[i].Columns[columnIdx].Meta.Raito.Mask.Name,
+					Name:     &node.Columns[columnIdx].Meta.Raito.Mask.Name,
 					Action:   utils.Ptr(models.AccessProviderActionMask),
 					WhatType: utils.Ptr(sdkTypes.WhoAndWhatTypeStatic),
 					DataSources: []sdkTypes.AccessProviderDataSourceInput{
 						{
 							DataSource: s.dataSourceId,
-							Type:       column.Meta.Raito.Mask.Type,
 						},
 					},
 					Source: &source,
@@ -419,15 +576,35 @@ func (s *DbtService) parseMasks(ctx context.Context, manifestData *manifest.Mani
 			}
 		}
 
+		if column.Meta.Raito.Mask.WhoRule != "" {
+			ruleErr := s.addWhoRule(ctx, masks[column.Meta.Raito.Mask.Name], column.Meta.Raito.Mask.WhoRule)
+			if ruleErr != nil {
+				err = multierror.Append(err, fmt.Errorf("mask %s of %s: %w", column.Meta.Raito.Mask.Name, node.UniqueId, ruleErr))
+
+				continue
+			}
+		}
+
 		masks[column.Meta.Raito.Mask.Name].Input.WhatDataObjects = append(masks[column.Meta.Raito.Mask.Name].Input.WhatDataObjects, sdkTypes.AccessProviderWhatInputDO{
 			DataObjectByName: []sdkTypes.AccessProviderWhatDoByNameInput{
 				{
-					Fullname:   fmt.Sprintf("%s.%s", doName, column.Name),
+					Fullname:   columnName,
 					Datasource: s.dataSourceId,
 				},
 			},
 		})
 
+		if column.Meta.Raito.Mask.PropagatedFrom != "" {
+			mask := masks[column.Meta.Raito.Mask.Name]
+			if mask.Propagated == nil {
+				mask.Propagated = make(map[string]string)
+			}
+
+			mask.Propagated[columnName] = column.Meta.Raito.Mask.PropagatedFrom
+		}
+
+		maskTables[column.Meta.Raito.Mask.Name] = append(maskTables[column.Meta.Raito.Mask.Name], maskTable{node: node, fullname: doName, maskType: maskType})
+
 		ownerErr := s.handleOwners(ctx, masks[column.Meta.Raito.Mask.Name], column.Meta.Raito.Mask.Owners)
 		if ownerErr != nil {
 			s.logger.Warn(fmt.Sprintf("handle owners for mask %s: %v", column.Meta.Raito.Mask.Name, ownerErr))
@@ -437,14 +614,195 @@ func (s *DbtService) parseMasks(ctx context.Context, manifestData *manifest.Mani
 	return err
 }
 
-func (s *DbtService) parseFilters(ctx context.Context, manifestData *manifest.Manifest, i string, filters map[string]*AccessProviderInput, source string, doName string, defaultLocks []sdkTypes.AccessProviderLockDataInput) error {
+// maskType returns the type of the mask of the column. If the mask does not define a type, the type is derived from the data type of the column.
+// A warning is logged if the type of the mask is mapped on other data types than the data type of the column.
+func (s *DbtService) maskType(node *manifest.Node, key string, column *manifest.Column, maskTypes MaskTypeMapping, catalog *manifest.Catalog) *string {
+	dataType := columnDataType(node, key, column, catalog)
+	if dataType == "" {
+		return column.Meta.Raito.Mask.Type
+	}
+
+	if column.Meta.Raito.Mask.Type != nil {
+		if incompatibleErr := maskTypes.IncompatibleMaskType(*column.Meta.Raito.Mask.Type, dataType); incompatibleErr != nil {
+			s.logger.Warn(fmt.Sprintf("mask %s on column %s of %s: %v", column.Meta.Raito.Mask.Name, columnName(key, column), node.UniqueId, incompatibleErr))
+		}
+
+		return column.Meta.Raito.Mask.Type
+	}
+
+	if maskType, found := maskTypes.MaskType(dataType); found {
+		return &maskType
+	}
+
+	return nil
+}
+
+// columnDataType returns the data type documented on the column, or else the type of the column in the catalog.
+func columnDataType(node *manifest.Node, key string, column *manifest.Column, catalog *manifest.Catalog) string {
+	if column.DataType != nil && *column.DataType != "" {
+		return *column.DataType
+	}
+
+	dataType, _ := catalog.ColumnType(node.UniqueId, columnName(key, column))
+
+	return dataType
+}
+
+// maskTable is the table of a column a mask is applied on, with the type of the mask for that column.
+type maskTable struct {
+	node     *manifest.Node
+	fullname string
+	maskType *string
+}
+
+// resolveMaskTypes sets the type of each mask to the type of its columns. Columns without (derived) type take the type of the other columns.
+// Masks whose columns have different types are reported, as a mask can only have one type.
+func resolveMaskTypes(masks map[string]*AccessProviderInput, maskTables map[string][]maskTable) error {
+	var err error
+
+	names := make([]string, 0, len(masks))
+	for name := range masks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		types := set.NewSet[string]()
+
+		for _, table := range maskTables[name] {
+			if table.maskType != nil {
+				types.Add(*table.maskType)
+			}
+		}
+
+		switch len(types) {
+		case 0:
+			continue
+		case 1:
+			maskType := types.Slice()[0]
+			masks[name].Input.DataSources[0].Type = &maskType
+		default:
+			typeList := types.Slice()
+			sort.Strings(typeList)
+
+			err = multierror.Append(err, fmt.Errorf("mask %s is applied on columns with different types (%s)", name, strings.Join(typeList, ", ")))
+		}
+	}
+
+	return err
+}
+
+// splitMasksPerTable splits the masks that are applied on columns of multiple tables into one mask per table, named `<mask>-<model>`,
+// for data sources that do not support masks across tables. The tables of a mask are compared by their exact fullname.
+// The tables of the split masks replace those of the original mask in maskTables, so their types are resolved per split mask.
+func (s *DbtService) splitMasksPerTable(projectName string, masks map[string]*AccessProviderInput, maskTables map[string][]maskTable) error {
+	var err error
+
+	names := make([]string, 0, len(maskTables))
+	for name := range maskTables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	splitMasks := make(map[string]*AccessProviderInput)
+	splitTables := make(map[string][]maskTable)
+
+	for _, name := range names {
+		tables := maskTables[name]
+
+		fullnames := set.NewSet[string]()
+		for _, table := range tables {
+			fullnames.Add(table.fullname)
+		}
+
+		if len(fullnames) <= 1 {
+			continue
+		}
+
+		mask := masks[name]
+		delete(masks, name)
+		delete(maskTables, name)
+
+		s.logger.Info(fmt.Sprintf("mask %s is applied on %d tables and is split into one mask per table", name, len(fullnames)))
+
+		for i, whatDo := range mask.Input.WhatDataObjects {
+			splitName := policy.ExpandedName(name, projectName, tables[i].node)
+
+			splitMask, found := splitMasks[splitName]
+			if !found {
+				splitMask = &AccessProviderInput{
+					Input:  mask.Input,
+					Owners: set.NewSet[string](mask.Owners.Slice()...),
+				}
+				splitMask.Input.Name = &splitName
+				splitMask.Input.WhatDataObjects = nil
+				splitMask.Input.Locks = append([]sdkTypes.AccessProviderLockDataInput(nil), mask.Input.Locks...)
+				splitMask.Input.DataSources = append([]sdkTypes.AccessProviderDataSourceInput(nil), mask.Input.DataSources...)
+
+				splitMasks[splitName] = splitMask
+			}
+
+			splitMask.Input.WhatDataObjects = append(splitMask.Input.WhatDataObjects, whatDo)
+
+			for _, doByName := range whatDo.DataObjectByName {
+				if origin, propagated := mask.Propagated[doByName.Fullname]; propagated {
+					if splitMask.Propagated == nil {
+						splitMask.Propagated = make(map[string]string)
+					}
+
+					splitMask.Propagated[doByName.Fullname] = origin
+				}
+			}
+			splitTables[splitName] = append(splitTables[splitName], tables[i])
+		}
+	}
+
+	for name, splitMask := range splitMasks {
+		if _, found := masks[name]; found {
+			err = multierror.Append(err, fmt.Errorf("mask %s is defined, but is also the name of a mask split per table", name))
+
+			continue
+		}
+
+		masks[name] = splitMask
+		maskTables[name] = splitTables[name]
+	}
+
+	return err
+}
+
+func (s *DbtService) parseFilters(ctx context.Context, node *manifest.Node, nodeFilters []manifest.Filter, filters map[string]*AccessProviderInput, source string, doName string, columnName func(column *manifest.Column) string, policyRuleVariables map[string]interface{}, defaultLocks []sdkTypes.AccessProviderLockDataInput) error {
 	var err error
 
-	for filterIdx, filter := range manifestData.Nodes[i].Meta.Raito.Filter {
+	for filterIdx, filter := range nodeFilters {
 		if _, found := filters[filter.Name]; !found {
+			policyRule, renderErr := renderPolicyRule(filter.PolicyRule, policyRuleVariables)
+			if renderErr != nil {
+				err = multierror.Append(err, fmt.Errorf("policy rule of filter %s of %s: %w", filter.Name, node.UniqueId, renderErr))
+
+				continue
+			}
+
+			policyRuleInput := &policyRule
+
+			var filterCriteria *bexpression.DataComparisonExpression
+
+			if filter.Criteria != "" {
+				criteria, criteriaErr := parseFilterCriteria(ctx, node, &filter, columnName)
+				if criteriaErr != nil {
+					err = multierror.Append(err, fmt.Errorf("filter %s of %s: %w", filter.Name, node.UniqueId, criteriaErr))
+
+					continue
+				}
+
+				policyRuleInput, filterCriteria = nil, criteria
+			}
+
 			filters[filter.Name] = &AccessProviderInput{
 				Input: sdkTypes.AccessProviderInput{
-					Name:     &manifestData.Nodes[i].Meta.Raito.Filter[filterIdx].Name,
+					Name:     &nodeFilters[filterIdx].Name,
 					Action:   utils.Ptr(models.AccessProviderActionFiltered),
 					WhatType: utils.Ptr(sdkTypes.WhoAndWhatTypeStatic),
 					DataSources: []sdkTypes.AccessProviderDataSourceInput{
@@ -452,8 +810,9 @@ func (s *DbtService) parseFilters(ctx context.Context, manifestData *manifest.Ma
 							DataSource: s.dataSourceId,
 						},
 					},
-					PolicyRule: &manifestData.Nodes[i].Meta.Raito.Filter[filterIdx].PolicyRule,
-					Source:     &source,
+					PolicyRule:     policyRuleInput,
+					FilterCriteria: filterCriteria,
+					Source:         &source,
 					WhatDataObjects: []sdkTypes.AccessProviderWhatInputDO{
 						{
 							DataObjectByName: []sdkTypes.AccessProviderWhatDoByNameInput{
@@ -469,6 +828,13 @@ func (s *DbtService) parseFilters(ctx context.Context, manifestData *manifest.Ma
 				Owners: set.NewSet[string](),
 			}
 
+			if filter.WhoRule != "" {
+				ruleErr := s.addWhoRule(ctx, filters[filter.Name], filter.WhoRule)
+				if ruleErr != nil {
+					err = multierror.Append(err, fmt.Errorf("filter %s of %s: %w", filter.Name, node.UniqueId, ruleErr))
+				}
+			}
+
 			ownerErr := s.handleOwners(ctx, filters[filter.Name], filter.Owners)
 			if ownerErr != nil {
 				s.logger.Warn(fmt.Sprintf("handle owners for filter %s: %v", filter.Name, ownerErr))
@@ -481,13 +847,18 @@ func (s *DbtService) parseFilters(ctx context.Context, manifestData *manifest.Ma
 	return err
 }
 
-func (s *DbtService) parseGrants(ctx context.Context, manifestData *manifest.Manifest, i string, grants map[string]*AccessProviderInput, source string, defaultLocks []sdkTypes.AccessProviderLockDataInput, doName string) (err error) {
-	for grandIdx, grant := range manifestData.Nodes[i].Meta.Raito.Grant {
+func (s *DbtService) parseGrants(ctx context.Context, node *manifest.Node, templates *policy.Templates, grants map[string]*AccessProviderInput, source string, defaultLocks []sdkTypes.AccessProviderLockDataInput, scopeFullname func(scope string) (string, error), resolveRef func(ref string) (string, error), downstreamFullnames func(depth int) ([]string, error)) (err error) {
+	nodeGrants, err := templates.ExpandGrants(node)
+	if err != nil {
+		return err
+	}
+
+	for grandIdx, grant := range nodeGrants {
 		if _, found := grants[grant.Name]; !found {
 			grants[grant.Name] = &AccessProviderInput{
 				Owners: set.NewSet[string](),
 				Input: sdkTypes.AccessProviderInput{
-					Name:     &manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Name,
+					Name:     &nodeGrants[grandIdx].Name,
 					Action:   utils.Ptr(models.AccessProviderActionGrant),
 					WhatType: utils.Ptr(sdkTypes.WhoAndWhatTypeStatic),
 					DataSources: []sdkTypes.AccessProviderDataSourceInput{
@@ -497,41 +868,81 @@ func (s *DbtService) parseGrants(ctx context.Context, manifestData *manifest.Man
 					},
 					Source:   &source,
 					Locks:    defaultLocks,
-					Category: manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Category,
+					Category: nodeGrants[grandIdx].Category,
 				},
 			}
 		}
 
-		if manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Type != nil {
-			if grants[grant.Name].Input.DataSources[0].Type != nil && *manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Type != *grants[grant.Name].Input.DataSources[0].Type {
-				err = multierror.Append(fmt.Errorf("grant %q already exists with different type (%q != %q)", grant.Name, *manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Type, *grants[grant.Name].Input.DataSources[0].Type))
+		if nodeGrants[grandIdx].Type != nil {
+			if grants[grant.Name].Input.DataSources[0].Type != nil && *nodeGrants[grandIdx].Type != *grants[grant.Name].Input.DataSources[0].Type {
+				err = multierror.Append(fmt.Errorf("grant %q already exists with different type (%q != %q)", grant.Name, *nodeGrants[grandIdx].Type, *grants[grant.Name].Input.DataSources[0].Type))
 
 				continue
 			}
 
-			grants[grant.Name].Input.DataSources[0].Type = manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Type
+			grants[grant.Name].Input.DataSources[0].Type = nodeGrants[grandIdx].Type
 		}
 
-		if manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Category != nil {
-			if grants[grant.Name].Input.Category != nil && *manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Category != *grants[grant.Name].Input.Category {
-				err = multierror.Append(fmt.Errorf("grant %q already exists with different category (%q != %q)", grant.Name, *manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Category, *grants[grant.Name].Input.Category))
+		if nodeGrants[grandIdx].Category != nil {
+			if grants[grant.Name].Input.Category != nil && *nodeGrants[grandIdx].Category != *grants[grant.Name].Input.Category {
+				err = multierror.Append(fmt.Errorf("grant %q already exists with different category (%q != %q)", grant.Name, *nodeGrants[grandIdx].Category, *grants[grant.Name].Input.Category))
 
 				continue
 			}
 
-			grants[grant.Name].Input.Category = manifestData.Nodes[i].Meta.Raito.Grant[grandIdx].Category
+			grants[grant.Name].Input.Category = nodeGrants[grandIdx].Category
 		}
 
-		grants[grant.Name].Input.WhatDataObjects = append(grants[grant.Name].Input.WhatDataObjects, sdkTypes.AccessProviderWhatInputDO{
-			Permissions:       array.Map(grant.Permissions, func(i string) *string { return &i }),
-			GlobalPermissions: array.Map(grant.GlobalPermissions, func(i string) *string { return &i }),
-			DataObjectByName: []sdkTypes.AccessProviderWhatDoByNameInput{
-				{
-					Fullname:   doName,
-					Datasource: s.dataSourceId,
-				},
-			},
-		})
+		if grant.WhoRule != "" {
+			ruleErr := s.addWhoRule(ctx, grants[grant.Name], grant.WhoRule)
+			if ruleErr != nil {
+				err = multierror.Append(err, fmt.Errorf("grant %q of %s: %w", grant.Name, node.UniqueId, ruleErr))
+
+				continue
+			}
+		}
+
+		if grant.WhatRule != "" {
+			ruleErr := s.addWhatRule(ctx, grants[grant.Name], &nodeGrants[grandIdx])
+			if ruleErr != nil {
+				err = multierror.Append(err, fmt.Errorf("grant %q of %s: %w", grant.Name, node.UniqueId, ruleErr))
+
+				continue
+			}
+		} else {
+			if grants[grant.Name].Input.WhatAbacRule != nil {
+				err = multierror.Append(err, fmt.Errorf("grant %q of %s: grant is already defined with a what_rule", grant.Name, node.UniqueId))
+
+				continue
+			}
+
+			fullname, scopeErr := scopeFullname(grant.Scope)
+			if scopeErr != nil {
+				err = multierror.Append(err, fmt.Errorf("grant %q of %s: %w", grant.Name, node.UniqueId, scopeErr))
+
+				continue
+			}
+
+			s.addWhatDataObject(grants[grant.Name], fullname, grant.Permissions, grant.GlobalPermissions)
+
+			for _, ref := range grant.Refs {
+				refName, refErr := resolveRef(ref)
+				if refErr != nil {
+					err = multierror.Append(err, fmt.Errorf("grant %q of %s: %w", grant.Name, node.UniqueId, refErr))
+
+					continue
+				}
+
+				s.addWhatDataObject(grants[grant.Name], refName, grant.Permissions, grant.GlobalPermissions)
+			}
+
+			propagateErr := s.propagateGrant(grants[grant.Name], &grant, downstreamFullnames)
+			if propagateErr != nil {
+				err = multierror.Append(err, fmt.Errorf("grant %q of %s: %w", grant.Name, node.UniqueId, propagateErr))
+
+				continue
+			}
+		}
 
 		ownerErr := s.handleOwners(ctx, grants[grant.Name], grant.Owners)
 		if ownerErr != nil {
@@ -542,6 +953,62 @@ func (s *DbtService) parseGrants(ctx context.Context, manifestData *manifest.Man
 	return err
 }
 
+// propagateGrant adds the models downstream of the node to a grant with `propagate: downstream`, with the same permissions as the node.
+func (s *DbtService) propagateGrant(ap *AccessProviderInput, grant *manifest.Grant, downstreamFullnames func(depth int) ([]string, error)) error {
+	switch grant.Propagate {
+	case "":
+		return nil
+	case manifest.GrantPropagateDownstream:
+	default:
+		return fmt.Errorf("unsupported propagate %q: expected %s", grant.Propagate, manifest.GrantPropagateDownstream)
+	}
+
+	if grant.Scope != "" {
+		return errors.New("propagate can not be combined with scope")
+	}
+
+	depth := grant.PropagateDepth
+	if depth <= 0 {
+		depth = -1
+	}
+
+	fullnames, err := downstreamFullnames(depth)
+	if err != nil {
+		return err
+	}
+
+	for _, fullname := range fullnames {
+		s.addWhatDataObject(ap, fullname, grant.Permissions, grant.GlobalPermissions)
+	}
+
+	return nil
+}
+
+// downstreamDataObjects returns the names of the selected data objects downstream of the node, up to the given depth.
+// Nodes that stop propagation are excluded, together with the nodes that are only downstream through them.
+func downstreamDataObjects(manifestData *manifest.Manifest, node *manifest.Node, depth int, selected map[string]struct{}, namer *naming.Namer) ([]string, error) {
+	descendants := manifestData.DescendantsWhere(node.UniqueId, depth, func(descendant *manifest.Node) bool {
+		return !descendant.Meta.Raito.StopPropagation
+	})
+
+	var result []string
+
+	for _, descendant := range descendants {
+		if _, found := selected[descendant.UniqueId]; !found {
+			continue
+		}
+
+		fullname, err := namer.DataObject(manifestData, descendant)
+		if err != nil {
+			return nil, fmt.Errorf("data object name of %s: %w", descendant.UniqueId, err)
+		}
+
+		result = append(result, fullname)
+	}
+
+	return result, nil
+}
+
 func (s *DbtService) handleOwners(ctx context.Context, ap *AccessProviderInput, owners []string) error {
 	if len(owners) > 0 {
 		users, ownerErr := s.getIdsOfUsers(ctx, owners...)
@@ -579,6 +1046,192 @@ func (s *DbtService) getIdsOfUsers(ctx context.Context, emailAddresses ...string
 	return result, err
 }
 
-func _source(projectName string) string {
+// addWhatRule makes the what of the grant dynamic, based on the what_rule of the grant. A grant with a what_rule can be defined
+// on multiple nodes if the rule and data object types are the same; the permissions are merged.
+func (s *DbtService) addWhatRule(ctx context.Context, ap *AccessProviderInput, grant *manifest.Grant) error {
+	if len(grant.Refs) > 0 || grant.Scope != "" || grant.Propagate != "" {
+		return errors.New("what_rule can not be combined with refs, scope or propagate")
+	}
+
+	if len(ap.Input.WhatDataObjects) > 0 {
+		return errors.New("what_rule can not be combined with static data objects of the same grant")
+	}
+
+	rule, err := expression.ParseTagRule(ctx, grant.WhatRule)
+	if err != nil {
+		return fmt.Errorf("what_rule: %w", err)
+	}
+
+	doTypes := grant.WhatDoTypes
+	if len(doTypes) == 0 {
+		doTypes = defaultWhatRuleDoTypes
+	}
+
+	abacRule := tagRuleToAbacExpression(rule)
+
+	if ap.Input.WhatAbacRule == nil {
+		ap.Input.WhatType = utils.Ptr(sdkTypes.WhoAndWhatTypeDynamic)
+		ap.Input.WhatAbacRule = &sdkTypes.WhatAbacRuleInput{
+			DoTypes: doTypes,
+			Rule:    abacRule,
+		}
+	} else if !reflect.DeepEqual(ap.Input.WhatAbacRule.Rule, abacRule) || !reflect.DeepEqual(ap.Input.WhatAbacRule.DoTypes, doTypes) {
+		return errors.New("grant already exists with a different what_rule or what_do_types")
+	}
+
+	ap.Input.WhatAbacRule.Permissions = mergePermissions(ap.Input.WhatAbacRule.Permissions, grant.Permissions)
+	ap.Input.WhatAbacRule.GlobalPermissions = mergePermissions(ap.Input.WhatAbacRule.GlobalPermissions, grant.GlobalPermissions)
+
+	return nil
+}
+
+// parseFilterCriteria parses the structured criteria of a filter and validates that all referenced columns are defined on the node.
+// Referenced columns get the same casing as the column fullnames. Unquoted references use the quoting of the column on the node, double-quoted references are always quoted.
+func parseFilterCriteria(ctx context.Context, node *manifest.Node, filter *manifest.Filter, columnName func(column *manifest.Column) string) (*bexpression.DataComparisonExpression, error) {
+	if filter.PolicyRule != "" {
+		return nil, errors.New("criteria can not be combined with policy_rule")
+	}
+
+	criteria, err := expression.ParseCriteria(ctx, filter.Criteria, func(name string, quoted bool) string {
+		if quoted {
+			return columnName(&manifest.Column{Name: name, Quote: &quoted})
+		}
+
+		for key, column := range node.Columns {
+			if strings.EqualFold(key, name) || strings.EqualFold(column.Name, name) {
+				if column.Name == "" {
+					column.Name = key
+				}
+
+				return columnName(&column)
+			}
+		}
+
+		return columnName(&manifest.Column{Name: name})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("criteria: %w", err)
+	}
+
+	columns, err := expression.ReferencedColumns(ctx, criteria)
+	if err != nil {
+		return nil, fmt.Errorf("criteria: %w", err)
+	}
+
+	for _, column := range columns {
+		if !hasColumn(node, column) {
+			return nil, fmt.Errorf("criteria: column %q is not defined on %s", column, node.UniqueId)
+		}
+	}
+
+	return criteria, nil
+}
+
+func hasColumn(node *manifest.Node, columnName string) bool {
+	for key, column := range node.Columns {
+		if strings.EqualFold(key, columnName) || strings.EqualFold(column.Name, columnName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addWhoRule makes the who of the access provider dynamic, based on a who_rule over user attributes, and locks the who in Raito Cloud.
+// If the access provider is defined on multiple nodes, the who_rule should be the same.
+func (s *DbtService) addWhoRule(ctx context.Context, ap *AccessProviderInput, whoRule string) error {
+	rule, err := expression.ParseAttributeRule(ctx, whoRule)
+	if err != nil {
+		return fmt.Errorf("who_rule: %w", err)
+	}
+
+	abacRule := attributeRuleToAbacExpression(rule)
+
+	if ap.Input.WhoAbacRule != nil {
+		if !reflect.DeepEqual(ap.Input.WhoAbacRule.Rule, abacRule) {
+			return errors.New("access provider already exists with a different who_rule")
+		}
+
+		return nil
+	}
+
+	ap.Input.WhoType = utils.Ptr(sdkTypes.WhoAndWhatTypeDynamic)
+	ap.Input.WhoAbacRule = &sdkTypes.WhoAbacRuleInput{Rule: abacRule}
+	ap.Input.Locks = append(ap.Input.Locks, sdkTypes.AccessProviderLockDataInput{
+		LockKey: sdkTypes.AccessProviderLockWholock,
+		Details: &sdkTypes.AccessProviderLockDetailsInput{
+			Reason: utils.Ptr(lockReason),
+		},
+	})
+
+	return nil
+}
+
+// addWhatDataObject adds the data object to the what of the access provider. If the data object is already part of the what,
+// e.g. a schema granted by multiple nodes, the permissions are merged.
+func (s *DbtService) addWhatDataObject(ap *AccessProviderInput, fullname string, permissions []string, globalPermissions []string) {
+	for i := range ap.Input.WhatDataObjects {
+		whatDo := &ap.Input.WhatDataObjects[i]
+
+		if len(whatDo.DataObjectByName) != 1 || whatDo.DataObjectByName[0].Fullname != fullname {
+			continue
+		}
+
+		whatDo.Permissions = mergePermissions(whatDo.Permissions, permissions)
+		whatDo.GlobalPermissions = mergePermissions(whatDo.GlobalPermissions, globalPermissions)
+
+		return
+	}
+
+	ap.Input.WhatDataObjects = append(ap.Input.WhatDataObjects, sdkTypes.AccessProviderWhatInputDO{
+		Permissions:       array.Map(permissions, func(i string) *string { return &i }),
+		GlobalPermissions: array.Map(globalPermissions, func(i string) *string { return &i }),
+		DataObjectByName: []sdkTypes.AccessProviderWhatDoByNameInput{
+			{
+				Fullname:   fullname,
+				Datasource: s.dataSourceId,
+			},
+		},
+	})
+}
+
+func mergePermissions(existing []*string, permissions []string) []*string {
+	existingSet := set.NewSet[string]()
+	for _, permission := range existing {
+		existingSet.Add(*permission)
+	}
+
+	for _, permission := range permissions {
+		if !existingSet.Contains(permission) {
+			existingSet.Add(permission)
+			existing = append(existing, utils.Ptr(permission))
+		}
+	}
+
+	return existing
+}
+
+// withoutRaitoMeta returns a copy of the node without the policies declared in the raito meta of the node and its columns.
+// It is used for older versions of versioned models, which are still part of the lineage (e.g. for propagated grants and masks).
+func withoutRaitoMeta(node *manifest.Node) *manifest.Node {
+	result := *node
+	result.Meta.Raito = manifest.RaitoMeta{StopPropagation: node.Meta.Raito.StopPropagation}
+	result.Columns = make(map[string]manifest.Column, len(node.Columns))
+
+	for key, column := range node.Columns {
+		column.Meta.Raito = manifest.RaitoMeta{}
+		result.Columns[key] = column
+	}
+
+	return &result
+}
+
+// _source returns the source of the access providers of the project. Each selection is synced under its own source,
+// so syncs of the same project with different selections do not remove each other's access providers.
+func _source(projectName string, selection *manifest.Selection) string {
+	if definition := selection.String(); definition != "" {
+		return fmt.Sprintf("%s-%s (%s)", dbtSource, projectName, definition)
+	}
+
 	return fmt.Sprintf("%s-%s", dbtSource, projectName)
 }
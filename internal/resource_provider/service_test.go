@@ -1,14 +1,22 @@
 package resource_provider
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/aws/smithy-go/ptr"
 	"github.com/hashicorp/go-hclog"
+	"github.com/raito-io/bexpression"
+	"github.com/raito-io/bexpression/base"
+	"github.com/raito-io/bexpression/datacomparison"
 	"github.com/raito-io/bexpression/utils"
 	"github.com/raito-io/cli/base/resource_provider"
 	"github.com/raito-io/golang-set/set"
@@ -17,8 +25,11 @@ import (
 	"github.com/raito-io/sdk-go/types/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+	"github.com/raito-io/cli-plugin-dbt/internal/policy"
 )
 
 func TestDbtService_createAndUpdateAccessProviders(t *testing.T) {
@@ -750,7 +761,7 @@ func TestDbtService_RunDbt(t *testing.T) {
 
 			tt.fields.setup(accessProviderClientMock, roleMock, userMock)
 
-			added, updated, removed, failures, err := s.RunDbt(tt.args.ctx, tt.args.dbtFile, "prefix.")
+			added, updated, removed, failures, err := s.RunDbt(tt.args.ctx, tt.args.dbtFile, &SyncOptions{Namer: naming.NewNamer("prefix.", naming.CasingAdapter)})
 			if !tt.wantErr(t, err, fmt.Sprintf("RunDbt(%v, %v)", tt.args.ctx, tt.args.dbtFile)) {
 				return
 			}
@@ -775,3 +786,1190 @@ func createDbtService(t *testing.T, dataSourceId string) (*DbtService, *MockAcce
 
 	return service, apMock, roleMock, userRepoMock
 }
+
+func TestDbtService_loadAccessProvidersFromMesh(t *testing.T) {
+	newManifest := func(projectName string, nodes ...manifest.Node) *manifest.Manifest {
+		m := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: projectName},
+			Nodes:    map[string]manifest.Node{},
+		}
+
+		for _, node := range nodes {
+			m.Nodes[node.UniqueId] = node
+		}
+
+		return m
+	}
+
+	core := newManifest("core",
+		manifest.Node{UniqueId: "model.core.customers", Database: "db", Schema: "core", Name: "customers", PackageName: "core", ResourceType: "model", Access: "public"},
+		manifest.Node{UniqueId: "model.core.orders", Database: "db", Schema: "core", Name: "orders", PackageName: "core", ResourceType: "model", Access: "protected"},
+	)
+
+	t.Run("cross project refs", func(t *testing.T) {
+		finance := newManifest("finance",
+			manifest.Node{UniqueId: "model.finance.revenue", Database: "db", Schema: "finance", Name: "revenue", PackageName: "finance", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+				Grant: []manifest.Grant{{Name: "finance_read", GlobalPermissions: []string{"READ"}, Refs: []string{"core.customers"}}},
+			}}},
+		)
+
+		mesh, err := manifest.NewMesh(core, finance)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		projects, err := s.loadAccessProvidersFromMesh(context.Background(), mesh, &SyncOptions{Namer: naming.NewNamer("prefix.", naming.CasingAdapter)})
+		require.NoError(t, err)
+		require.Len(t, projects, 2)
+
+		assert.Equal(t, "dbt-core", projects[0].source)
+		assert.Empty(t, projects[0].grants)
+
+		assert.Equal(t, "dbt-finance", projects[1].source)
+		require.Contains(t, projects[1].grants, "finance_read")
+
+		var fullnames []string
+		for _, whatDo := range projects[1].grants["finance_read"].Input.WhatDataObjects {
+			fullnames = append(fullnames, whatDo.DataObjectByName[0].Fullname)
+		}
+
+		assert.Equal(t, []string{"prefix.db.finance.revenue", "prefix.db.core.customers"}, fullnames)
+	})
+
+	t.Run("ref to protected model", func(t *testing.T) {
+		finance := newManifest("finance",
+			manifest.Node{UniqueId: "model.finance.revenue", Database: "db", Schema: "finance", Name: "revenue", PackageName: "finance", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+				Grant: []manifest.Grant{{Name: "finance_read", GlobalPermissions: []string{"READ"}, Refs: []string{"core.orders"}}},
+			}}},
+		)
+
+		mesh, err := manifest.NewMesh(core, finance)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, err = s.loadAccessProvidersFromMesh(context.Background(), mesh, &SyncOptions{Namer: naming.NewNamer("prefix.", naming.CasingAdapter)})
+		assert.ErrorIs(t, err, manifest.ErrRefNotAccessible)
+	})
+
+	t.Run("same access provider in multiple projects", func(t *testing.T) {
+		marketing := newManifest("marketing",
+			manifest.Node{UniqueId: "model.marketing.campaigns", Database: "db", Schema: "marketing", Name: "campaigns", PackageName: "marketing", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+				Grant: []manifest.Grant{{Name: "analyst_read", GlobalPermissions: []string{"READ"}}},
+			}}},
+		)
+		finance := newManifest("finance",
+			manifest.Node{UniqueId: "model.finance.revenue", Database: "db", Schema: "finance", Name: "revenue", PackageName: "finance", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+				Grant: []manifest.Grant{{Name: "analyst_read", GlobalPermissions: []string{"READ"}}},
+			}}},
+		)
+
+		mesh, err := manifest.NewMesh(marketing, finance)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, err = s.loadAccessProvidersFromMesh(context.Background(), mesh, &SyncOptions{Namer: naming.NewNamer("prefix.", naming.CasingAdapter)})
+		assert.ErrorContains(t, err, `grant "analyst_read" is defined in multiple projects (dbt-marketing and dbt-finance)`)
+	})
+
+	t.Run("unknown unique id in the policies file", func(t *testing.T) {
+		policiesFile := filepath.Join(t.TempDir(), "policies.yml")
+		require.NoError(t, os.WriteFile(policiesFile, []byte(`
+grants:
+  - name: core_read
+    permissions: [SELECT]
+    unique_ids: [model.core.customers, model.core.unknown]
+`), 0600))
+
+		policies, err := policy.LoadPolicies(policiesFile)
+		require.NoError(t, err)
+
+		finance := newManifest("finance",
+			manifest.Node{UniqueId: "model.finance.revenue", Database: "db", Schema: "finance", Name: "revenue", PackageName: "finance", ResourceType: "model"},
+		)
+
+		mesh, err := manifest.NewMesh(core, finance)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, err = s.loadAccessProvidersFromMesh(context.Background(), mesh, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter), Policies: policies})
+		require.Error(t, err)
+
+		// The unique ids are validated against the whole mesh, so the error is reported once instead of once per project
+		assert.Equal(t, 1, strings.Count(err.Error(), `unique id "model.core.unknown" is not defined in the manifest`))
+		assert.NotContains(t, err.Error(), `"model.core.customers"`)
+	})
+
+	t.Run("disjoint selections", func(t *testing.T) {
+		shop := newManifest("shop",
+			manifest.Node{UniqueId: "model.shop.revenue", Database: "db", Schema: "finance", Name: "revenue", PackageName: "shop", ResourceType: "model", Tags: []string{"finance"}, Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+				Grant: []manifest.Grant{{Name: "finance_read", GlobalPermissions: []string{"READ"}}},
+			}}},
+			manifest.Node{UniqueId: "model.shop.campaigns", Database: "db", Schema: "marketing", Name: "campaigns", PackageName: "shop", ResourceType: "model", Tags: []string{"marketing"}, Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+				Grant: []manifest.Grant{{Name: "marketing_read", GlobalPermissions: []string{"READ"}}},
+			}}},
+		)
+
+		mesh, err := manifest.NewMesh(shop)
+		require.NoError(t, err)
+
+		sync := func(selectExpression string) *projectAccessProviders {
+			selection, selectionErr := manifest.ParseSelection(selectExpression, "")
+			require.NoError(t, selectionErr)
+
+			s, _, _, _ := createDbtService(t, "dsId1")
+
+			projects, loadErr := s.loadAccessProvidersFromMesh(context.Background(), mesh, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter), Selection: selection})
+			require.NoError(t, loadErr)
+			require.Len(t, projects, 1)
+
+			return projects[0]
+		}
+
+		finance := sync("tag:finance")
+		marketing := sync("tag:marketing")
+
+		assert.Equal(t, "dbt-shop (select tag:finance)", finance.source)
+		require.Len(t, finance.grants, 1)
+		assert.Contains(t, finance.grants, "finance_read")
+
+		assert.Equal(t, "dbt-shop (select tag:marketing)", marketing.source)
+		require.Len(t, marketing.grants, 1)
+		assert.Contains(t, marketing.grants, "marketing_read")
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_VersionedModels(t *testing.T) {
+	grant := manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{{Name: "orders_read", GlobalPermissions: []string{"READ"}}}}}
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.orders.v1": {Database: "db", Schema: "finance", Name: "orders", Alias: "orders_v1", ResourceType: "model", Version: "1", LatestVersion: "2", Meta: grant},
+			"model.shop.orders.v2": {Database: "db", Schema: "finance", Name: "orders", Alias: "orders_v2", ResourceType: "model", Version: "2", LatestVersion: "2", Meta: grant},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		latestVersionOnly bool
+		want              []string
+	}{
+		{name: "all versions", want: []string{"db.finance.orders_v1", "db.finance.orders_v2"}},
+		{name: "latest version only", latestVersionOnly: true, want: []string{"db.finance.orders_v2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _, _ := createDbtService(t, "dsId1")
+
+			_, grants, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+				Namer:             naming.NewNamer("", naming.CasingAdapter),
+				LatestVersionOnly: tt.latestVersionOnly,
+			})
+			require.NoError(t, err)
+			require.Contains(t, grants, "orders_read")
+
+			var fullnames []string
+			for _, whatDo := range grants["orders_read"].Input.WhatDataObjects {
+				fullnames = append(fullnames, whatDo.DataObjectByName[0].Fullname)
+			}
+
+			assert.Equal(t, tt.want, fullnames)
+		})
+	}
+
+	t.Run("older versions keep propagated and exposure grants", func(t *testing.T) {
+		versionedData := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.stg_orders": {Database: "db", Schema: "staging", Name: "stg_orders", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+					Grant: []manifest.Grant{{Name: "staging_read", GlobalPermissions: []string{"READ"}, Propagate: manifest.GrantPropagateDownstream}},
+				}}},
+				"model.shop.orders.v1": {Database: "db", Schema: "finance", Name: "orders", Alias: "orders_v1", ResourceType: "model", Version: "1", LatestVersion: "2", Meta: grant,
+					DependsOn: manifest.NodeDependsOn{Nodes: []string{"model.shop.stg_orders"}},
+					Columns:   map[string]manifest.Column{"email": {Name: "email", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: "email_mask"}}}}},
+				},
+				"model.shop.orders.v2": {Database: "db", Schema: "finance", Name: "orders", Alias: "orders_v2", ResourceType: "model", Version: "2", LatestVersion: "2", Meta: grant,
+					DependsOn: manifest.NodeDependsOn{Nodes: []string{"model.shop.stg_orders"}},
+				},
+			},
+			Exposures: map[string]manifest.Exposure{
+				"exposure.shop.legacy_dashboard": {
+					Name:      "legacy_dashboard",
+					DependsOn: manifest.NodeDependsOn{Nodes: []string{"model.shop.orders.v1"}},
+					Meta:      manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{{Name: "dashboard_read", GlobalPermissions: []string{"READ"}}}}},
+				},
+			},
+		}
+
+		versionedMesh, meshErr := manifest.NewMesh(versionedData)
+		require.NoError(t, meshErr)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, grants, _, masks, loadErr := s.loadAccessProvidersFromManifest(context.Background(), versionedMesh, versionedData, &SyncOptions{
+			Namer:             naming.NewNamer("", naming.CasingAdapter),
+			LatestVersionOnly: true,
+		})
+		require.NoError(t, loadErr)
+
+		fullnames := func(name string) []string {
+			var result []string
+			for _, whatDo := range grants[name].Input.WhatDataObjects {
+				result = append(result, whatDo.DataObjectByName[0].Fullname)
+			}
+
+			sort.Strings(result)
+
+			return result
+		}
+
+		assert.Equal(t, []string{"db.finance.orders_v2"}, fullnames("orders_read"))
+		assert.Equal(t, []string{"db.finance.orders_v1", "db.finance.orders_v2", "db.staging.stg_orders"}, fullnames("staging_read"))
+		assert.Equal(t, []string{"db.finance.orders_v1"}, fullnames("dashboard_read"))
+		assert.Empty(t, masks)
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_GrantScope(t *testing.T) {
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.orders": {Database: "db", Schema: "marts", Name: "orders", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{
+				{Name: "marts_usage", Permissions: []string{"USAGE"}, Scope: manifest.GrantScopeSchema},
+				{Name: "db_usage", Permissions: []string{"USAGE"}, Scope: manifest.GrantScopeDatabase},
+			}}}},
+			"model.shop.customers": {Database: "db", Schema: "marts", Name: "customers", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{
+				{Name: "marts_usage", Permissions: []string{"USAGE", "MONITOR"}, Scope: manifest.GrantScopeSchema},
+			}}}},
+			"model.shop.stg_orders": {Database: "db", Schema: "staging", Name: "stg_orders", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{
+				{Name: "db_usage", Permissions: []string{"USAGE"}, Scope: manifest.GrantScopeDatabase},
+			}}}},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	s, _, _, _ := createDbtService(t, "dsId1")
+
+	_, grants, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{Namer: naming.NewNamer("prefix.", naming.CasingAdapter)})
+	require.NoError(t, err)
+
+	assert.Equal(t, []sdkTypes.AccessProviderWhatInputDO{
+		{
+			Permissions:       []*string{utils.Ptr("USAGE"), utils.Ptr("MONITOR")},
+			GlobalPermissions: []*string{},
+			DataObjectByName:  []sdkTypes.AccessProviderWhatDoByNameInput{{Fullname: "prefix.db.marts", Datasource: "dsId1"}},
+		},
+	}, grants["marts_usage"].Input.WhatDataObjects)
+
+	assert.Equal(t, []sdkTypes.AccessProviderWhatInputDO{
+		{
+			Permissions:       []*string{utils.Ptr("USAGE")},
+			GlobalPermissions: []*string{},
+			DataObjectByName:  []sdkTypes.AccessProviderWhatDoByNameInput{{Fullname: "prefix.db", Datasource: "dsId1"}},
+		},
+	}, grants["db_usage"].Input.WhatDataObjects)
+
+	t.Run("invalid scope", func(t *testing.T) {
+		invalid := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders": {Database: "db", Schema: "marts", Name: "orders", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{
+					{Name: "marts_usage", Permissions: []string{"USAGE"}, Scope: "warehouse"},
+				}}}},
+			},
+		}
+
+		_, _, _, _, err = s.loadAccessProvidersFromManifest(context.Background(), mesh, invalid, &SyncOptions{Namer: naming.NewNamer("prefix.", naming.CasingAdapter)})
+		assert.ErrorContains(t, err, "unsupported scope")
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_WhatRule(t *testing.T) {
+	grant := func(name string, whatRule string, permissions ...string) manifest.Meta {
+		return manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{{Name: name, WhatRule: whatRule, Permissions: permissions}}}}
+	}
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.orders":    {Database: "db", Schema: "finance", Name: "orders", ResourceType: "model", Meta: grant("finance_read", "domain:finance and not pii", "SELECT")},
+			"model.shop.invoices":  {Database: "db", Schema: "finance", Name: "invoices", ResourceType: "model", Meta: grant("finance_read", "domain:finance and not pii", "SELECT", "REFERENCES")},
+			"model.shop.customers": {Database: "db", Schema: "sales", Name: "customers", ResourceType: "model", Meta: grant("sales_read", "", "SELECT")},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	s, _, _, _ := createDbtService(t, "dsId1")
+
+	_, grants, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+	require.NoError(t, err)
+
+	require.Contains(t, grants, "finance_read")
+	assert.Equal(t, sdkTypes.WhoAndWhatTypeDynamic, *grants["finance_read"].Input.WhatType)
+	assert.Empty(t, grants["finance_read"].Input.WhatDataObjects)
+	assert.Equal(t, &sdkTypes.WhatAbacRuleInput{
+		DoTypes:     []string{"table", "view"},
+		Permissions: []*string{utils.Ptr("SELECT"), utils.Ptr("REFERENCES")},
+		Rule: sdkTypes.AbacComparisonExpressionInput{Aggregator: &sdkTypes.AbacComparisonExpressionAggregatorInput{
+			Operator: sdkTypes.AbacComparisonExpressionAggregatorOperatorAnd,
+			Operands: []sdkTypes.AbacComparisonExpressionInput{
+				{Comparison: &sdkTypes.AbacComparisonExpressionComparisonInput{
+					Operator:     sdkTypes.AbacComparisonExpressionComparisonOperatorHastag,
+					LeftOperand:  "domain",
+					RightOperand: sdkTypes.AbacComparisonExpressionOperandInput{Literal: &sdkTypes.AbacComparisonExpressionLiteralInput{String: utils.Ptr("finance")}},
+				}},
+				{UnaryExpression: &sdkTypes.AbacComparisonExpressionUnaryExpressionInput{
+					Operator: sdkTypes.AbacComparisonExpressionUnaryOperatorNot,
+					Operand: sdkTypes.AbacComparisonExpressionInput{Comparison: &sdkTypes.AbacComparisonExpressionComparisonInput{
+						Operator:     sdkTypes.AbacComparisonExpressionComparisonOperatorHastag,
+						LeftOperand:  "tag",
+						RightOperand: sdkTypes.AbacComparisonExpressionOperandInput{Literal: &sdkTypes.AbacComparisonExpressionLiteralInput{String: utils.Ptr("pii")}},
+					}},
+				}},
+			},
+		}},
+	}, grants["finance_read"].Input.WhatAbacRule)
+
+	require.Contains(t, grants, "sales_read")
+	assert.Equal(t, sdkTypes.WhoAndWhatTypeStatic, *grants["sales_read"].Input.WhatType)
+	assert.Nil(t, grants["sales_read"].Input.WhatAbacRule)
+
+	t.Run("conflicting what rules", func(t *testing.T) {
+		conflicting := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders":   {Database: "db", Schema: "finance", Name: "orders", ResourceType: "model", Meta: grant("finance_read", "domain:finance", "SELECT")},
+				"model.shop.invoices": {Database: "db", Schema: "finance", Name: "invoices", ResourceType: "model", Meta: grant("finance_read", "domain:sales", "SELECT")},
+			},
+		}
+
+		_, _, _, _, err = s.loadAccessProvidersFromManifest(context.Background(), mesh, conflicting, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+		assert.ErrorContains(t, err, "grant already exists with a different what_rule")
+	})
+
+	t.Run("invalid what rule", func(t *testing.T) {
+		invalid := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders": {Database: "db", Schema: "finance", Name: "orders", ResourceType: "model", Meta: grant("finance_read", "domain:finance and", "SELECT")},
+			},
+		}
+
+		_, _, _, _, err = s.loadAccessProvidersFromManifest(context.Background(), mesh, invalid, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+		assert.ErrorContains(t, err, `grant "finance_read" of model.shop.orders: what_rule: invalid expression`)
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_WhoRule(t *testing.T) {
+	department := func(value string) sdkTypes.AbacComparisonExpressionInput {
+		return sdkTypes.AbacComparisonExpressionInput{Comparison: &sdkTypes.AbacComparisonExpressionComparisonInput{
+			Operator:     sdkTypes.AbacComparisonExpressionComparisonOperatorEquals,
+			LeftOperand:  "department",
+			RightOperand: sdkTypes.AbacComparisonExpressionOperandInput{Literal: &sdkTypes.AbacComparisonExpressionLiteralInput{String: utils.Ptr(value)}},
+		}}
+	}
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.orders": {
+				Database: "db", Schema: "finance", Name: "orders", ResourceType: "model",
+				Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+					Grant:  []manifest.Grant{{Name: "finance_read", Permissions: []string{"SELECT"}, WhoRule: "department = Finance and clearance >= 2"}},
+					Filter: []manifest.Filter{{Name: "eu_only", PolicyRule: "region = 'EU'", WhoRule: "department != 'Finance'"}},
+				}},
+				Columns: map[string]manifest.Column{
+					"email": {Name: "email", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: "email_mask", WhoRule: "department = Marketing"}}}},
+				},
+			},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	s, _, _, _ := createDbtService(t, "dsId1")
+
+	_, grants, filters, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+	require.NoError(t, err)
+
+	whoLock := sdkTypes.AccessProviderLockDataInput{LockKey: sdkTypes.AccessProviderLockWholock, Details: &sdkTypes.AccessProviderLockDetailsInput{Reason: utils.Ptr(lockReason)}}
+
+	require.Contains(t, grants, "finance_read")
+	assert.Equal(t, sdkTypes.WhoAndWhatTypeDynamic, *grants["finance_read"].Input.WhoType)
+	assert.Contains(t, grants["finance_read"].Input.Locks, whoLock)
+	assert.Equal(t, &sdkTypes.WhoAbacRuleInput{Rule: sdkTypes.AbacComparisonExpressionInput{Aggregator: &sdkTypes.AbacComparisonExpressionAggregatorInput{
+		Operator: sdkTypes.AbacComparisonExpressionAggregatorOperatorAnd,
+		Operands: []sdkTypes.AbacComparisonExpressionInput{
+			department("Finance"),
+			{Comparison: &sdkTypes.AbacComparisonExpressionComparisonInput{
+				Operator:     sdkTypes.AbacComparisonExpressionComparisonOperatorGreaterthanorequal,
+				LeftOperand:  "clearance",
+				RightOperand: sdkTypes.AbacComparisonExpressionOperandInput{Literal: &sdkTypes.AbacComparisonExpressionLiteralInput{Int: utils.Ptr(2)}},
+			}},
+		},
+	}}}, grants["finance_read"].Input.WhoAbacRule)
+
+	require.Contains(t, filters, "eu_only")
+	assert.Equal(t, sdkTypes.WhoAndWhatTypeDynamic, *filters["eu_only"].Input.WhoType)
+	assert.Equal(t, sdkTypes.AbacComparisonExpressionComparisonOperatorNotequals, filters["eu_only"].Input.WhoAbacRule.Rule.Comparison.Operator)
+	assert.Contains(t, filters["eu_only"].Input.Locks, whoLock)
+
+	require.Contains(t, masks, "email_mask")
+	assert.Equal(t, &sdkTypes.WhoAbacRuleInput{Rule: department("Marketing")}, masks["email_mask"].Input.WhoAbacRule)
+	assert.Contains(t, masks["email_mask"].Input.Locks, whoLock)
+
+	t.Run("invalid who rule", func(t *testing.T) {
+		invalid := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders": {Database: "db", Schema: "finance", Name: "orders", ResourceType: "model", Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+					Grant: []manifest.Grant{{Name: "finance_read", Permissions: []string{"SELECT"}, WhoRule: "department"}},
+				}}},
+			},
+		}
+
+		_, _, _, _, err = s.loadAccessProvidersFromManifest(context.Background(), mesh, invalid, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+		assert.ErrorContains(t, err, `grant "finance_read" of model.shop.orders: who_rule: invalid expression: position 0: attribute "department" should be compared with a value`)
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_FilterCriteria(t *testing.T) {
+	node := func(filter manifest.Filter) manifest.Node {
+		return manifest.Node{
+			Database: "db", Schema: "sales", Name: "orders", ResourceType: "model",
+			Meta: manifest.Meta{Raito: manifest.RaitoMeta{Filter: []manifest.Filter{filter}}},
+			Columns: map[string]manifest.Column{
+				"region": {Name: "region"},
+				"amount": {Name: "amount"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		adapter string
+		filter  manifest.Filter
+		want    *bexpression.DataComparisonExpression
+		wantErr string
+	}{
+		{
+			name:   "valid criteria",
+			filter: manifest.Filter{Name: "eu_orders", Criteria: "region = 'EU' and amount > 100"},
+			want: &bexpression.DataComparisonExpression{Aggregator: &bexpression.DataComparisonAggregator{
+				Operator: base.AggregatorOperatorAnd,
+				Operands: []bexpression.DataComparisonExpression{
+					{Comparison: &datacomparison.DataComparison{
+						Operator:     datacomparison.ComparisonOperatorEqual,
+						LeftOperand:  datacomparison.Operand{Reference: &datacomparison.Reference{EntityType: datacomparison.EntityTypeColumnReferenceByName, EntityID: "region"}},
+						RightOperand: datacomparison.Operand{Literal: &datacomparison.Literal{Str: utils.Ptr("EU")}},
+					}},
+					{Comparison: &datacomparison.DataComparison{
+						Operator:     datacomparison.ComparisonOperatorGreaterThan,
+						LeftOperand:  datacomparison.Operand{Reference: &datacomparison.Reference{EntityType: datacomparison.EntityTypeColumnReferenceByName, EntityID: "amount"}},
+						RightOperand: datacomparison.Operand{Literal: &datacomparison.Literal{Int: utils.Ptr(100)}},
+					}},
+				},
+			}},
+		},
+		{
+			name:    "adapter casing and double-quoted identifiers",
+			adapter: naming.AdapterSnowflake,
+			filter:  manifest.Filter{Name: "eu_orders", Criteria: `region = 'EU' and "Amount" > 100`},
+			want: &bexpression.DataComparisonExpression{Aggregator: &bexpression.DataComparisonAggregator{
+				Operator: base.AggregatorOperatorAnd,
+				Operands: []bexpression.DataComparisonExpression{
+					{Comparison: &datacomparison.DataComparison{
+						Operator:     datacomparison.ComparisonOperatorEqual,
+						LeftOperand:  datacomparison.Operand{Reference: &datacomparison.Reference{EntityType: datacomparison.EntityTypeColumnReferenceByName, EntityID: "REGION"}},
+						RightOperand: datacomparison.Operand{Literal: &datacomparison.Literal{Str: utils.Ptr("EU")}},
+					}},
+					{Comparison: &datacomparison.DataComparison{
+						Operator:     datacomparison.ComparisonOperatorGreaterThan,
+						LeftOperand:  datacomparison.Operand{Reference: &datacomparison.Reference{EntityType: datacomparison.EntityTypeColumnReferenceByName, EntityID: "Amount"}},
+						RightOperand: datacomparison.Operand{Literal: &datacomparison.Literal{Int: utils.Ptr(100)}},
+					}},
+				},
+			}},
+		},
+		{
+			name:    "unknown column",
+			filter:  manifest.Filter{Name: "eu_orders", Criteria: "country = 'BE'"},
+			wantErr: `filter eu_orders of model.shop.orders: criteria: column "country" is not defined on model.shop.orders`,
+		},
+		{
+			name:    "criteria and policy rule",
+			filter:  manifest.Filter{Name: "eu_orders", Criteria: "region = 'EU'", PolicyRule: "region = 'EU'"},
+			wantErr: "criteria can not be combined with policy_rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifestData := &manifest.Manifest{
+				Metadata: manifest.Metadata{ProjectName: "shop", AdapterType: tt.adapter},
+				Nodes:    map[string]manifest.Node{"model.shop.orders": node(tt.filter)},
+			}
+
+			mesh, err := manifest.NewMesh(manifestData)
+			require.NoError(t, err)
+
+			s, _, _, _ := createDbtService(t, "dsId1")
+
+			_, _, filters, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Contains(t, filters, "eu_orders")
+			assert.Nil(t, filters["eu_orders"].Input.PolicyRule)
+			assert.Equal(t, tt.want, filters["eu_orders"].Input.FilterCriteria)
+		})
+	}
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_FilterTemplates(t *testing.T) {
+	regionFilter := manifest.Meta{Raito: manifest.RaitoMeta{Filter: []manifest.Filter{{Template: "region"}}}}
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.orders":       {Database: "db", Schema: "sales", Name: "orders", ResourceType: "model", Meta: regionFilter, Columns: map[string]manifest.Column{"region": {Name: "region"}}},
+			"model.shop.customers":    {Database: "db", Schema: "sales", Name: "customers", ResourceType: "model", Meta: regionFilter, Columns: map[string]manifest.Column{"region": {Name: "country_region"}}},
+			"model.shop_utils.orders": {Database: "db", Schema: "utils", Name: "orders", PackageName: "shop_utils", ResourceType: "model", Meta: regionFilter, Columns: map[string]manifest.Column{"region": {Name: "region"}}},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	s, _, _, _ := createDbtService(t, "dsId1")
+
+	_, _, filters, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+		Namer:     naming.NewNamer("", naming.CasingAdapter),
+		Templates: &policy.Templates{Filters: map[string]manifest.Filter{"region": {PolicyRule: "{{ this.column.region }} = 'EU'"}}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, filters, 3)
+	assert.Equal(t, "region = 'EU'", *filters["region-orders"].Input.PolicyRule)
+	assert.Equal(t, "db.sales.orders", filters["region-orders"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+	assert.Equal(t, "country_region = 'EU'", *filters["region-customers"].Input.PolicyRule)
+	assert.Equal(t, "db.sales.customers", filters["region-customers"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+	require.Len(t, filters["region-shop_utils-orders"].Input.WhatDataObjects, 1)
+	assert.Equal(t, "db.utils.orders", filters["region-shop_utils-orders"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_CrossTableMasks(t *testing.T) {
+	emailMask := func(name string) map[string]manifest.Column {
+		return map[string]manifest.Column{
+			"email": {Name: "email", Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: name, Owners: []string{}}}}},
+		}
+	}
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.customers":     {Database: "db", Schema: "sales", Name: "customers", ResourceType: "model", Columns: emailMask("email_mask")},
+			"model.shop.customers_eu":  {Database: "db", Schema: "sales", Name: "customers_eu", ResourceType: "model", Columns: emailMask("email_mask")},
+			"model.shop.stg_customers": {Database: "db", Schema: "staging", Name: "stg_customers", ResourceType: "model", Columns: emailMask("stg_email_mask")},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	maskedColumns := func(mask *AccessProviderInput) []string {
+		var result []string
+		for _, whatDo := range mask.Input.WhatDataObjects {
+			result = append(result, whatDo.DataObjectByName[0].Fullname)
+		}
+
+		sort.Strings(result)
+
+		return result
+	}
+
+	t.Run("split per table", func(t *testing.T) {
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, _, _, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+		require.NoError(t, err)
+
+		require.Len(t, masks, 3)
+		assert.Equal(t, "email_mask-customers", *masks["email_mask-customers"].Input.Name)
+		assert.Equal(t, []string{"db.sales.customers.email"}, maskedColumns(masks["email_mask-customers"]))
+		assert.Equal(t, []string{"db.sales.customers_eu.email"}, maskedColumns(masks["email_mask-customers_eu"]))
+		assert.Equal(t, []string{"db.staging.stg_customers.email"}, maskedColumns(masks["stg_email_mask"]))
+	})
+
+	t.Run("cross table masks", func(t *testing.T) {
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, _, _, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter), CrossTableMasks: true})
+		require.NoError(t, err)
+
+		require.Len(t, masks, 2)
+		assert.Equal(t, []string{"db.sales.customers.email", "db.sales.customers_eu.email"}, maskedColumns(masks["email_mask"]))
+	})
+
+	t.Run("same model name in another package", func(t *testing.T) {
+		packages := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.customers":       {Database: "db", Schema: "sales", Name: "customers", PackageName: "shop", ResourceType: "model", Columns: emailMask("email_mask")},
+				"model.shop_utils.customers": {Database: "db", Schema: "utils", Name: "customers", PackageName: "shop_utils", ResourceType: "model", Columns: emailMask("email_mask")},
+			},
+		}
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, _, _, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, packages, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+		require.NoError(t, err)
+
+		require.Len(t, masks, 2)
+		assert.Equal(t, []string{"db.sales.customers.email"}, maskedColumns(masks["email_mask-customers"]))
+		assert.Equal(t, []string{"db.utils.customers.email"}, maskedColumns(masks["email_mask-shop_utils-customers"]))
+	})
+
+	t.Run("split name conflicts with mask", func(t *testing.T) {
+		conflicting := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.customers":    {Database: "db", Schema: "sales", Name: "customers", ResourceType: "model", Columns: emailMask("email_mask")},
+				"model.shop.customers_eu": {Database: "db", Schema: "sales", Name: "customers_eu", ResourceType: "model", Columns: emailMask("email_mask")},
+				"model.shop.orders":       {Database: "db", Schema: "sales", Name: "orders", ResourceType: "model", Columns: emailMask("email_mask-customers")},
+			},
+		}
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, _, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, conflicting, &SyncOptions{Namer: naming.NewNamer("", naming.CasingAdapter)})
+		assert.ErrorContains(t, err, "mask email_mask-customers is defined, but is also the name of a mask split per table")
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_MaskTypes(t *testing.T) {
+	mask := func(name string, dataType string, maskType *string) manifest.Column {
+		return manifest.Column{Name: name, DataType: &dataType, Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: name + "_mask", Type: maskType}}}}
+	}
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.customers": {Database: "db", Schema: "sales", Name: "customers", ResourceType: "model", Columns: map[string]manifest.Column{
+				"email":      mask("email", "varchar(256)", nil),
+				"birth_date": mask("birth_date", "DATE", nil),
+				"age":        mask("age", "integer", nil),
+				"created_at": mask("created_at", "timestamp", utils.Ptr("SHA256")),
+			}},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	s, _, _, _ := createDbtService(t, "dsId1")
+
+	_, _, _, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+		Namer:     naming.NewNamer("", naming.CasingAdapter),
+		MaskTypes: MaskTypeMapping{"string": "SHA256", "date": "NULL"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, utils.Ptr("SHA256"), masks["email_mask"].Input.DataSources[0].Type)
+	assert.Equal(t, utils.Ptr("NULL"), masks["birth_date_mask"].Input.DataSources[0].Type)
+	assert.Nil(t, masks["age_mask"].Input.DataSources[0].Type)
+	assert.Equal(t, utils.Ptr("SHA256"), masks["created_at_mask"].Input.DataSources[0].Type)
+
+	piiMask := func(dataType *string) manifest.Column {
+		return manifest.Column{Name: "pii", DataType: dataType, Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: "pii_mask"}}}}
+	}
+
+	piiManifest := func(customersType *string, ordersType *string) *manifest.Manifest {
+		return &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.customers": {UniqueId: "model.shop.customers", Database: "db", Schema: "sales", Name: "customers", ResourceType: "model", Columns: map[string]manifest.Column{"pii": piiMask(customersType)}},
+				"model.shop.orders":    {UniqueId: "model.shop.orders", Database: "db", Schema: "sales", Name: "orders", ResourceType: "model", Columns: map[string]manifest.Column{"pii": piiMask(ordersType)}},
+			},
+		}
+	}
+
+	loadMasks := func(t *testing.T, manifestData *manifest.Manifest, options *SyncOptions) (map[string]*AccessProviderInput, error) {
+		t.Helper()
+
+		mesh, err := manifest.NewMesh(manifestData)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		options.Namer = naming.NewNamer("", naming.CasingAdapter)
+		options.MaskTypes = MaskTypeMapping{"string": "SHA256", "date": "NULL"}
+
+		_, _, _, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, options)
+
+		return masks, err
+	}
+
+	t.Run("column without data type", func(t *testing.T) {
+		masks, err := loadMasks(t, piiManifest(nil, utils.Ptr("varchar")), &SyncOptions{CrossTableMasks: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, utils.Ptr("SHA256"), masks["pii_mask"].Input.DataSources[0].Type)
+	})
+
+	t.Run("different types split per table", func(t *testing.T) {
+		masks, err := loadMasks(t, piiManifest(utils.Ptr("varchar"), utils.Ptr("date")), &SyncOptions{})
+		require.NoError(t, err)
+
+		require.Len(t, masks, 2)
+		assert.Equal(t, utils.Ptr("SHA256"), masks["pii_mask-customers"].Input.DataSources[0].Type)
+		assert.Equal(t, utils.Ptr("NULL"), masks["pii_mask-orders"].Input.DataSources[0].Type)
+	})
+
+	t.Run("different types across tables", func(t *testing.T) {
+		_, err := loadMasks(t, piiManifest(utils.Ptr("varchar"), utils.Ptr("date")), &SyncOptions{CrossTableMasks: true})
+		assert.ErrorContains(t, err, "mask pii_mask is applied on columns with different types (NULL, SHA256)")
+	})
+
+	t.Run("data types of the catalog", func(t *testing.T) {
+		catalog := &manifest.Catalog{Nodes: map[string]manifest.CatalogTable{
+			"model.shop.customers": {Columns: map[string]manifest.CatalogColumn{"PII": {Name: "PII", Type: "TEXT"}}},
+			"model.shop.orders":    {Columns: map[string]manifest.CatalogColumn{"PII": {Name: "PII", Type: "DATE"}}},
+		}}
+
+		masks, err := loadMasks(t, piiManifest(nil, utils.Ptr("varchar")), &SyncOptions{Catalog: catalog})
+		require.NoError(t, err)
+
+		assert.Equal(t, utils.Ptr("SHA256"), masks["pii_mask-customers"].Input.DataSources[0].Type)
+		assert.Equal(t, utils.Ptr("SHA256"), masks["pii_mask-orders"].Input.DataSources[0].Type, "documented data type takes precedence over the catalog")
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_Rules(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.yml")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`
+rules:
+  - match: pii
+    mask:
+      name: pii_hash
+  - match: finance
+    grant:
+      name: finance_readers
+      permissions: [SELECT]
+`), 0600))
+
+	rules, err := policy.LoadRules(context.Background(), rulesFile, "")
+	require.NoError(t, err)
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.orders": {Database: "db", Schema: "sales", Name: "orders", ResourceType: "model", Tags: []string{"finance"}, Columns: map[string]manifest.Column{
+				"amount": {Name: "amount"},
+			}},
+			"model.shop.customers": {Database: "db", Schema: "sales", Name: "customers", ResourceType: "model", Columns: map[string]manifest.Column{
+				"email": {Name: "email", Tags: []string{"pii"}},
+				"phone": {Name: "phone", Tags: []string{"pii"}, Meta: manifest.Meta{Raito: manifest.RaitoMeta{Mask: &manifest.Mask{Name: "phone_mask"}}}},
+			}},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	s, _, _, _ := createDbtService(t, "dsId1")
+
+	_, grants, _, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+		Namer:           naming.NewNamer("", naming.CasingAdapter),
+		CrossTableMasks: true,
+		Rules:           rules,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, grants, 1)
+	assert.Equal(t, "db.sales.orders", grants["finance_readers"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+
+	require.Len(t, masks, 2)
+	assert.Equal(t, "db.sales.customers.email", masks["pii_hash"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+	assert.Equal(t, "db.sales.customers.phone", masks["phone_mask"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+
+	assert.Nil(t, manifestData.Nodes["model.shop.customers"].Columns["email"].Meta.Raito.Mask)
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_Policies(t *testing.T) {
+	policiesFile := filepath.Join(t.TempDir(), "policies.yml")
+	require.NoError(t, os.WriteFile(policiesFile, []byte(`
+grants:
+  - name: finance_readers
+    permissions: [SELECT]
+    select: tag:finance
+masks:
+  - name: email_mask
+    columns: [email]
+    unique_ids: [model.shop.customers]
+`), 0600))
+
+	policies, err := policy.LoadPolicies(policiesFile)
+	require.NoError(t, err)
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"model.shop.orders": {UniqueId: "model.shop.orders", Database: "db", Schema: "sales", Name: "orders", ResourceType: "model", Tags: []string{"finance"}, Meta: manifest.Meta{Raito: manifest.RaitoMeta{
+				Grant: []manifest.Grant{{Name: "finance_readers", Permissions: []string{"SELECT", "INSERT"}}},
+			}}},
+			"model.shop.invoices": {UniqueId: "model.shop.invoices", Database: "db", Schema: "sales", Name: "invoices", ResourceType: "model", Tags: []string{"finance"}},
+			"model.shop.customers": {UniqueId: "model.shop.customers", Database: "db", Schema: "sales", Name: "customers", ResourceType: "model", Columns: map[string]manifest.Column{
+				"email": {Name: "email"},
+			}},
+		},
+	}
+
+	mesh, err := manifest.NewMesh(manifestData)
+	require.NoError(t, err)
+
+	s, _, _, _ := createDbtService(t, "dsId1")
+
+	_, grants, _, masks, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+		Namer:    naming.NewNamer("", naming.CasingAdapter),
+		Policies: policies,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, grants, 1)
+	require.Len(t, grants["finance_readers"].Input.WhatDataObjects, 2)
+
+	for _, whatDo := range grants["finance_readers"].Input.WhatDataObjects {
+		switch whatDo.DataObjectByName[0].Fullname {
+		case "db.sales.orders":
+			assert.ElementsMatch(t, []*string{ptr.String("SELECT"), ptr.String("INSERT")}, whatDo.Permissions)
+		case "db.sales.invoices":
+			assert.Equal(t, []*string{ptr.String("SELECT")}, whatDo.Permissions)
+		default:
+			t.Errorf("unexpected data object %s", whatDo.DataObjectByName[0].Fullname)
+		}
+	}
+
+	require.Len(t, masks, 1)
+	assert.Equal(t, "db.sales.customers.email", masks["email_mask"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_GrantTemplates(t *testing.T) {
+	templates := &policy.Templates{Grants: map[string]manifest.Grant{
+		"finance_read": {Permissions: []string{"SELECT"}, Category: ptr.String("finance")},
+	}}
+
+	grantTemplate := func(permissions ...string) manifest.Meta {
+		return manifest.Meta{Raito: manifest.RaitoMeta{Grant: []manifest.Grant{{Template: "finance_read", Permissions: permissions}}}}
+	}
+
+	t.Run("expanded into one grant", func(t *testing.T) {
+		manifestData := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders":   {UniqueId: "model.shop.orders", Database: "db", Schema: "sales", Name: "orders", ResourceType: "model", Meta: grantTemplate()},
+				"model.shop.invoices": {UniqueId: "model.shop.invoices", Database: "db", Schema: "sales", Name: "invoices", ResourceType: "model", Meta: grantTemplate("INSERT")},
+			},
+		}
+
+		mesh, err := manifest.NewMesh(manifestData)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, grants, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer:     naming.NewNamer("", naming.CasingAdapter),
+			Templates: templates,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, grants, 1)
+		assert.Equal(t, "finance_read", *grants["finance_read"].Input.Name)
+		assert.Equal(t, ptr.String("finance"), grants["finance_read"].Input.Category)
+
+		permissions := make(map[string][]*string)
+		for _, whatDo := range grants["finance_read"].Input.WhatDataObjects {
+			permissions[whatDo.DataObjectByName[0].Fullname] = whatDo.Permissions
+		}
+
+		assert.Equal(t, map[string][]*string{
+			"db.sales.orders":   {ptr.String("SELECT")},
+			"db.sales.invoices": {ptr.String("SELECT"), ptr.String("INSERT")},
+		}, permissions)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		manifestData := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders": {UniqueId: "model.shop.orders", Database: "db", Schema: "sales", Name: "orders", ResourceType: "model", Meta: grantTemplate()},
+			},
+		}
+
+		mesh, err := manifest.NewMesh(manifestData)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, _, _, _, err = s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer: naming.NewNamer("", naming.CasingAdapter),
+		})
+		assert.ErrorContains(t, err, `parse grants: grant template "finance_read" of model.shop.orders not found`)
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_GrantPropagation(t *testing.T) {
+	model := func(name string, dependsOn []string, meta manifest.RaitoMeta) manifest.Node {
+		return manifest.Node{UniqueId: "model.shop." + name, Database: "db", Schema: "sales", Name: name, ResourceType: "model", DependsOn: manifest.NodeDependsOn{Nodes: dependsOn}, Meta: manifest.Meta{Raito: meta}}
+	}
+
+	propagatedGrant := func(depth int) manifest.RaitoMeta {
+		return manifest.RaitoMeta{Grant: []manifest.Grant{{Name: "finance_readers", Permissions: []string{"SELECT"}, Propagate: manifest.GrantPropagateDownstream, PropagateDepth: depth}}}
+	}
+
+	tests := []struct {
+		name        string
+		grantDepth  int
+		stopAt      string
+		wantObjects []string
+		wantErr     string
+	}{
+		{
+			name:        "all downstream models",
+			wantObjects: []string{"db.sales.orders", "db.sales.revenue", "db.sales.revenue_report", "db.sales.stg_orders"},
+		},
+		{
+			name:        "depth limited",
+			grantDepth:  1,
+			wantObjects: []string{"db.sales.orders", "db.sales.stg_orders"},
+		},
+		{
+			name:        "stop propagation",
+			stopAt:      "revenue",
+			wantObjects: []string{"db.sales.orders", "db.sales.stg_orders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifestData := &manifest.Manifest{
+				Metadata: manifest.Metadata{ProjectName: "shop"},
+				Nodes: map[string]manifest.Node{
+					"model.shop.stg_orders":     model("stg_orders", nil, propagatedGrant(tt.grantDepth)),
+					"model.shop.orders":         model("orders", []string{"model.shop.stg_orders"}, manifest.RaitoMeta{}),
+					"model.shop.revenue":        model("revenue", []string{"model.shop.orders"}, manifest.RaitoMeta{StopPropagation: tt.stopAt == "revenue"}),
+					"model.shop.revenue_report": model("revenue_report", []string{"model.shop.revenue"}, manifest.RaitoMeta{}),
+					"model.shop.customers":      model("customers", nil, manifest.RaitoMeta{}),
+				},
+			}
+
+			mesh, err := manifest.NewMesh(manifestData)
+			require.NoError(t, err)
+
+			s, _, _, _ := createDbtService(t, "dsId1")
+
+			_, grants, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+				Namer: naming.NewNamer("", naming.CasingAdapter),
+			})
+			require.NoError(t, err)
+
+			var objects []string
+			for _, whatDo := range grants["finance_readers"].Input.WhatDataObjects {
+				objects = append(objects, whatDo.DataObjectByName[0].Fullname)
+				assert.Equal(t, []*string{ptr.String("SELECT")}, whatDo.Permissions)
+			}
+
+			sort.Strings(objects)
+			assert.Equal(t, tt.wantObjects, objects)
+		})
+	}
+
+	t.Run("combined with scope", func(t *testing.T) {
+		manifestData := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.stg_orders": model("stg_orders", nil, manifest.RaitoMeta{Grant: []manifest.Grant{{Name: "finance_readers", Scope: manifest.GrantScopeSchema, Propagate: manifest.GrantPropagateDownstream}}}),
+			},
+		}
+
+		mesh, err := manifest.NewMesh(manifestData)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, _, _, _, err = s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer: naming.NewNamer("", naming.CasingAdapter),
+		})
+		assert.ErrorContains(t, err, `grant "finance_readers" of model.shop.stg_orders: propagate can not be combined with scope`)
+	})
+}
+
+func TestDbtService_loadAccessProvidersFromManifest_Exposures(t *testing.T) {
+	model := func(name string) manifest.Node {
+		return manifest.Node{UniqueId: "model.shop." + name, Database: "db", Schema: "sales", Name: name, ResourceType: "model"}
+	}
+
+	exposure := func(meta manifest.RaitoMeta, dependsOn ...string) manifest.Exposure {
+		return manifest.Exposure{
+			UniqueId:  "exposure.shop.revenue_dashboard",
+			Name:      "revenue_dashboard",
+			Type:      "dashboard",
+			Owner:     manifest.ExposureOwner{Name: "BI team", Email: "bi@shop.com"},
+			DependsOn: manifest.NodeDependsOn{Nodes: dependsOn},
+			Meta:      manifest.Meta{Raito: meta},
+		}
+	}
+
+	t.Run("grant covers the models the exposure depends on", func(t *testing.T) {
+		manifestData := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders":    model("orders"),
+				"model.shop.revenue":   model("revenue"),
+				"model.shop.customers": model("customers"),
+			},
+			Exposures: map[string]manifest.Exposure{
+				"exposure.shop.revenue_dashboard": exposure(
+					manifest.RaitoMeta{Grant: []manifest.Grant{{Name: "dashboard_readers", Permissions: []string{"SELECT"}}}},
+					"model.shop.orders", "model.shop.revenue", "source.shop.raw.payments",
+				),
+			},
+		}
+
+		mesh, err := manifest.NewMesh(manifestData)
+		require.NoError(t, err)
+
+		s, _, _, userMock := createDbtService(t, "dsId1")
+		userMock.EXPECT().GetUserByEmail(mock.Anything, "bi@shop.com").Return(&sdkTypes.User{Id: "bi-user-id"}, nil)
+
+		_, grants, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer: naming.NewNamer("", naming.CasingAdapter),
+		})
+		require.NoError(t, err)
+
+		require.Len(t, grants, 1)
+		assert.ElementsMatch(t, []string{"bi-user-id"}, grants["dashboard_readers"].Owners.Slice())
+
+		var objects []string
+		for _, whatDo := range grants["dashboard_readers"].Input.WhatDataObjects {
+			objects = append(objects, whatDo.DataObjectByName[0].Fullname)
+			assert.Equal(t, []*string{ptr.String("SELECT")}, whatDo.Permissions)
+		}
+
+		sort.Strings(objects)
+		assert.Equal(t, []string{"db.sales.orders", "db.sales.revenue"}, objects)
+	})
+
+	t.Run("warns for dependencies that cannot be granted", func(t *testing.T) {
+		manifestData := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders":    model("orders"),
+				"model.shop.customers": model("customers"),
+			},
+			Exposures: map[string]manifest.Exposure{
+				"exposure.shop.revenue_dashboard": exposure(
+					manifest.RaitoMeta{Grant: []manifest.Grant{{Name: "dashboard_readers", Permissions: []string{"SELECT"}}}},
+					"model.shop.orders", "model.shop.customers", "source.shop.raw.payments",
+				),
+			},
+		}
+
+		mesh, err := manifest.NewMesh(manifestData)
+		require.NoError(t, err)
+
+		selection, err := manifest.ParseSelection("", "customers")
+		require.NoError(t, err)
+
+		var logs bytes.Buffer
+
+		s, _, _, userMock := createDbtService(t, "dsId1")
+		s.logger = hclog.New(&hclog.LoggerOptions{Output: &logs, Level: hclog.Warn})
+		userMock.EXPECT().GetUserByEmail(mock.Anything, "bi@shop.com").Return(&sdkTypes.User{Id: "bi-user-id"}, nil)
+
+		_, grants, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer:     naming.NewNamer("", naming.CasingAdapter),
+			Selection: selection,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, grants, 1)
+		require.Len(t, grants["dashboard_readers"].Input.WhatDataObjects, 1)
+		assert.Equal(t, "db.sales.orders", grants["dashboard_readers"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+
+		assert.Contains(t, logs.String(), "Grants of exposure exposure.shop.revenue_dashboard do not cover model.shop.customers: the node is not selected")
+		assert.Contains(t, logs.String(), "Grants of exposure exposure.shop.revenue_dashboard do not cover source.shop.raw.payments: only models, seeds and snapshots can be granted")
+		assert.NotContains(t, logs.String(), "model.shop.orders")
+	})
+
+	t.Run("grant template", func(t *testing.T) {
+		manifestData := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders": model("orders"),
+			},
+			Exposures: map[string]manifest.Exposure{
+				"exposure.shop.revenue_dashboard": exposure(
+					manifest.RaitoMeta{Grant: []manifest.Grant{{Template: "finance_read", Owners: []string{"finance@shop.com"}}}},
+					"model.shop.orders",
+				),
+			},
+		}
+
+		mesh, err := manifest.NewMesh(manifestData)
+		require.NoError(t, err)
+
+		s, _, _, userMock := createDbtService(t, "dsId1")
+		userMock.EXPECT().GetUserByEmail(mock.Anything, "finance@shop.com").Return(&sdkTypes.User{Id: "finance-user-id"}, nil)
+
+		_, grants, _, _, err := s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer:     naming.NewNamer("", naming.CasingAdapter),
+			Templates: &policy.Templates{Grants: map[string]manifest.Grant{"finance_read": {Permissions: []string{"SELECT"}}}},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, grants, 1)
+		assert.ElementsMatch(t, []string{"finance-user-id"}, grants["finance_read"].Owners.Slice())
+		require.Len(t, grants["finance_read"].Input.WhatDataObjects, 1)
+		assert.Equal(t, "db.sales.orders", grants["finance_read"].Input.WhatDataObjects[0].DataObjectByName[0].Fullname)
+	})
+
+	t.Run("only grants", func(t *testing.T) {
+		manifestData := &manifest.Manifest{
+			Metadata: manifest.Metadata{ProjectName: "shop"},
+			Nodes: map[string]manifest.Node{
+				"model.shop.orders": model("orders"),
+			},
+			Exposures: map[string]manifest.Exposure{
+				"exposure.shop.revenue_dashboard": exposure(manifest.RaitoMeta{Filter: []manifest.Filter{{Name: "eu_only", PolicyRule: "region = 'EU'"}}}, "model.shop.orders"),
+			},
+		}
+
+		mesh, err := manifest.NewMesh(manifestData)
+		require.NoError(t, err)
+
+		s, _, _, _ := createDbtService(t, "dsId1")
+
+		_, _, _, _, err = s.loadAccessProvidersFromManifest(context.Background(), mesh, manifestData, &SyncOptions{
+			Namer: naming.NewNamer("", naming.CasingAdapter),
+		})
+		assert.ErrorContains(t, err, "exposure exposure.shop.revenue_dashboard: only grants are supported on exposures")
+	})
+}
@@ -3,11 +3,15 @@ package resource_provider
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/raito-io/cli/base/resource_provider"
 	"github.com/raito-io/cli/base/wrappers"
 
 	"github.com/raito-io/cli-plugin-dbt/internal/constants"
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+	"github.com/raito-io/cli-plugin-dbt/internal/policy"
 	"github.com/raito-io/cli-plugin-dbt/internal/utils"
 )
 
@@ -24,7 +28,83 @@ func NewResourceSyncer(service *DbtService) *ResourceSyncer {
 }
 
 func (r ResourceSyncer) UpdateResources(ctx context.Context, config *resource_provider.UpdateResourceInput) (*resource_provider.UpdateResourceResult, error) {
-	addedResources, updatedResource, deletedResources, failures, err := r.service.RunDbt(ctx, config.ConfigMap.GetString(constants.ManifestParameterName), utils.GetFullnamePrefix(config.ConfigMap))
+	manifestLocation, err := utils.GetManifestLocation(config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	loadOptions, err := utils.GetManifestLoadOptions(config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := utils.GetSelection(config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	namer, err := utils.GetNamer(config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	latestVersionOnly, err := parseModelVersions(config.ConfigMap.GetString(constants.ModelVersionsParameterName))
+	if err != nil {
+		return nil, err
+	}
+
+	policyRuleVariables, err := naming.ParseMapping(config.ConfigMap.GetString(constants.PolicyRuleVariablesParameterName))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", constants.PolicyRuleVariablesParameterName, err)
+	}
+
+	target, err := naming.ParseMapping(config.ConfigMap.GetString(constants.DbtTargetParameterName))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", constants.DbtTargetParameterName, err)
+	}
+
+	templates, err := policy.LoadTemplates(config.ConfigMap.GetString(constants.TemplatesFileParameterName))
+	if err != nil {
+		return nil, err
+	}
+
+	maskTypes, err := ParseMaskTypeMapping(config.ConfigMap.GetString(constants.MaskTypeMappingParameterName))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", constants.MaskTypeMappingParameterName, err)
+	}
+
+	catalogLoadOptions := append(slices.Clone(loadOptions), manifest.WithBearerToken(config.ConfigMap.GetString(constants.CatalogTokenParameterName)))
+
+	catalog, err := manifest.LoadCatalogs(ctx, config.ConfigMap.GetString(constants.CatalogParameterName), catalogLoadOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := policy.LoadRules(ctx, config.ConfigMap.GetString(constants.RulesFileParameterName), config.ConfigMap.GetString(constants.TagSplitKey))
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := policy.LoadPolicies(config.ConfigMap.GetString(constants.PoliciesFileParameterName))
+	if err != nil {
+		return nil, err
+	}
+
+	options := &SyncOptions{
+		Namer:               namer,
+		Selection:           selection,
+		LatestVersionOnly:   latestVersionOnly,
+		PolicyRuleVariables: policyRuleVariables,
+		Target:              target,
+		Templates:           templates,
+		CrossTableMasks:     config.ConfigMap.GetBool(constants.CrossTableMasksParameterName),
+		MaskTypes:           maskTypes,
+		Catalog:             catalog,
+		Rules:               rules,
+		Policies:            policies,
+	}
+
+	addedResources, updatedResource, deletedResources, failures, err := r.service.RunDbt(ctx, manifestLocation, options, loadOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("running dbt: %w", err)
 	}
@@ -36,3 +116,15 @@ func (r ResourceSyncer) UpdateResources(ctx context.Context, config *resource_pr
 		Failures:       int32(failures),         //nolint:gosec // safe to cast
 	}, nil
 }
+
+// parseModelVersions returns whether the policies of versioned models are only applied on the latest version.
+func parseModelVersions(value string) (bool, error) {
+	switch value {
+	case "", constants.ModelVersionsAll:
+		return false, nil
+	case constants.ModelVersionsLatest:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid %s %q: expected %s or %s", constants.ModelVersionsParameterName, value, constants.ModelVersionsAll, constants.ModelVersionsLatest)
+	}
+}
@@ -3,6 +3,8 @@
 package tags
 
 import (
+	context "context"
+
 	manifest "github.com/raito-io/cli-plugin-dbt/internal/manifest"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -20,9 +22,16 @@ func (_m *MockParser) EXPECT() *MockParser_Expecter {
 	return &MockParser_Expecter{mock: &_m.Mock}
 }
 
-// LoadManifest provides a mock function with given fields: path
-func (_m *MockParser) LoadManifest(path string) (*manifest.Manifest, error) {
-	ret := _m.Called(path)
+// LoadManifest provides a mock function with given fields: ctx, location, ops
+func (_m *MockParser) LoadManifest(ctx context.Context, location string, ops ...func(*manifest.LoadOptions)) (*manifest.Manifest, error) {
+	_va := make([]interface{}, len(ops))
+	for _i := range ops {
+		_va[_i] = ops[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, location)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LoadManifest")
@@ -30,19 +39,19 @@ func (_m *MockParser) LoadManifest(path string) (*manifest.Manifest, error) {
 
 	var r0 *manifest.Manifest
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*manifest.Manifest, error)); ok {
-		return rf(path)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...func(*manifest.LoadOptions)) (*manifest.Manifest, error)); ok {
+		return rf(ctx, location, ops...)
 	}
-	if rf, ok := ret.Get(0).(func(string) *manifest.Manifest); ok {
-		r0 = rf(path)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...func(*manifest.LoadOptions)) *manifest.Manifest); ok {
+		r0 = rf(ctx, location, ops...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*manifest.Manifest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(path)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...func(*manifest.LoadOptions)) error); ok {
+		r1 = rf(ctx, location, ops...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -56,14 +65,23 @@ type MockParser_LoadManifest_Call struct {
 }
 
 // LoadManifest is a helper method to define mock.On call
-//   - path string
-func (_e *MockParser_Expecter) LoadManifest(path interface{}) *MockParser_LoadManifest_Call {
-	return &MockParser_LoadManifest_Call{Call: _e.mock.On("LoadManifest", path)}
+//   - ctx context.Context
+//   - location string
+//   - ops ...func(*manifest.LoadOptions)
+func (_e *MockParser_Expecter) LoadManifest(ctx interface{}, location interface{}, ops ...interface{}) *MockParser_LoadManifest_Call {
+	return &MockParser_LoadManifest_Call{Call: _e.mock.On("LoadManifest",
+		append([]interface{}{ctx, location}, ops...)...)}
 }
 
-func (_c *MockParser_LoadManifest_Call) Run(run func(path string)) *MockParser_LoadManifest_Call {
+func (_c *MockParser_LoadManifest_Call) Run(run func(ctx context.Context, location string, ops ...func(*manifest.LoadOptions))) *MockParser_LoadManifest_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		variadicArgs := make([]func(*manifest.LoadOptions), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*manifest.LoadOptions))
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
 	})
 	return _c
 }
@@ -73,7 +91,7 @@ func (_c *MockParser_LoadManifest_Call) Return(_a0 *manifest.Manifest, _a1 error
 	return _c
 }
 
-func (_c *MockParser_LoadManifest_Call) RunAndReturn(run func(string) (*manifest.Manifest, error)) *MockParser_LoadManifest_Call {
+func (_c *MockParser_LoadManifest_Call) RunAndReturn(run func(context.Context, string, ...func(*manifest.LoadOptions)) (*manifest.Manifest, error)) *MockParser_LoadManifest_Call {
 	_c.Call.Return(run)
 	return _c
 }
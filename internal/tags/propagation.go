@@ -0,0 +1,164 @@
+package tags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raito-io/cli/base/wrappers"
+	"github.com/raito-io/golang-set/set"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+)
+
+// parsePropagateTagKeys parses a comma separated list of tag keys.
+func parsePropagateTagKeys(value string) set.Set[string] {
+	result := set.NewSet[string]()
+
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			result.Add(key)
+		}
+	}
+
+	return result
+}
+
+// propagateTagsFromManifest adds the tags with one of the propagated keys to all data objects downstream of the node or source that declares them.
+// Column tags are only propagated to downstream columns with the same name, as long as each node in between has a column with that name.
+// Propagated tags are added with a distinct source, so they can be told apart from declared tags. Tags already declared on a data object are not propagated to it.
+// If no keys are propagated, no tags are added, but the source is still returned.
+func (t *TagImportService) propagateTagsFromManifest(manifestData *manifest.Manifest, namer *naming.Namer, selection *manifest.Selection, tagsHandler wrappers.TagHandler, keys set.Set[string]) (string, error) {
+	source := fmt.Sprintf("dbt-%s-propagated", manifestData.Metadata.ProjectName)
+
+	if len(keys) == 0 {
+		return source, nil
+	}
+
+	nodes, err := manifestData.SelectDataObjectNodes(selection)
+	if err != nil {
+		return "", fmt.Errorf("select nodes: %w", err)
+	}
+
+	for _, node := range nodes {
+		declared := set.NewSet[string](node.Tags...)
+		declared.Add(node.Config.Tags...)
+
+		propagated := set.NewSet[string]()
+
+		for _, ancestor := range manifestData.LineageAncestors(node.UniqueId, -1) {
+			propagated.Add(t.propagatedTags(keys, ancestor.Tags, ancestor.Config.Tags)...)
+		}
+
+		propagated.RemoveAll(declared.Slice()...)
+
+		if len(propagated) > 0 {
+			doName, nameErr := namer.DataObject(manifestData, node)
+			if nameErr != nil {
+				return "", fmt.Errorf("data object name of %s: %w", node.UniqueId, nameErr)
+			}
+
+			t.logger.Debug(fmt.Sprintf("Propagate tags %v to %s", propagated.Slice(), node.UniqueId))
+
+			err = t.addTags(tagsHandler, doName, source, propagated)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		for columnName := range node.Columns {
+			column := node.Columns[columnName]
+			if column.Name == "" {
+				column.Name = columnName
+			}
+
+			columnTags := t.propagatedColumnTags(manifestData, node, column.Name, keys)
+
+			columnTags.RemoveAll(column.Tags...)
+			columnTags.RemoveAll(column.Config.Tags...)
+
+			if len(columnTags) == 0 {
+				continue
+			}
+
+			columnFullName, nameErr := namer.Column(manifestData, node, &column)
+			if nameErr != nil {
+				return "", fmt.Errorf("column name of %s.%s: %w", node.UniqueId, columnName, nameErr)
+			}
+
+			t.logger.Debug(fmt.Sprintf("Propagate tags %v to column %s of %s", columnTags.Slice(), column.Name, node.UniqueId))
+
+			err = t.addTags(tagsHandler, columnFullName, source, columnTags)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return source, nil
+}
+
+// propagatedColumnTags returns the tags, with one of the propagated keys, of the columns with the same name upstream of the column.
+func (t *TagImportService) propagatedColumnTags(manifestData *manifest.Manifest, node *manifest.Node, columnName string, keys set.Set[string]) set.Set[string] {
+	result := set.NewSet[string]()
+
+	visited := set.NewSet[string](node.UniqueId)
+	current := []*manifest.Node{node}
+
+	for len(current) > 0 {
+		var next []*manifest.Node
+
+		for _, child := range current {
+			for _, parent := range manifestData.LineageParents(child.UniqueId) {
+				if visited.Contains(parent.UniqueId) {
+					continue
+				}
+
+				visited.Add(parent.UniqueId)
+
+				column, found := findColumn(parent, columnName)
+				if !found {
+					continue
+				}
+
+				result.Add(t.propagatedTags(keys, column.Tags, column.Config.Tags)...)
+				next = append(next, parent)
+			}
+		}
+
+		current = next
+	}
+
+	return result
+}
+
+// propagatedTags returns the tags with one of the propagated keys.
+func (t *TagImportService) propagatedTags(keys set.Set[string], tags ...[]string) []string {
+	var result []string
+
+	for _, list := range tags {
+		for _, tagString := range list {
+			if key, _ := t.tagSeparator.Parse(tagString); keys.Contains(key) {
+				result = append(result, tagString)
+			}
+		}
+	}
+
+	return result
+}
+
+// findColumn returns the column of the node with the given name, ignoring the casing.
+func findColumn(node *manifest.Node, name string) (manifest.Column, bool) {
+	for key, column := range node.Columns {
+		columnName := column.Name
+		if columnName == "" {
+			columnName = key
+		}
+
+		if strings.EqualFold(columnName, name) {
+			return column, true
+		}
+	}
+
+	return manifest.Column{}, false
+}
@@ -0,0 +1,121 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/raito-io/bexpression/utils"
+	"github.com/raito-io/cli/base/tag"
+	"github.com/raito-io/cli/base/wrappers/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
+)
+
+func Test_propagateTagsFromManifest(t *testing.T) {
+	column := func(name string, tags ...string) manifest.Column {
+		return manifest.Column{Name: name, Tags: tags}
+	}
+
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"seed.shop.raw_customers": {Database: "db", Schema: "raw", Name: "raw_customers", ResourceType: "seed", Tags: []string{"classification:confidential", "owner:ops"}, Columns: map[string]manifest.Column{
+				"email": column("email", "classification:pii", "owner:crm"),
+			}},
+			"model.shop.stg_customers": {Database: "db", Schema: "staging", Name: "stg_customers", ResourceType: "model", DependsOn: manifest.NodeDependsOn{Nodes: []string{"seed.shop.raw_customers"}}, Columns: map[string]manifest.Column{
+				"email": column("email"),
+			}},
+			"model.shop.customers": {Database: "db", Schema: "marts", Name: "customers", ResourceType: "model", Tags: []string{"classification:confidential"}, DependsOn: manifest.NodeDependsOn{Nodes: []string{"model.shop.stg_customers"}}, Columns: map[string]manifest.Column{
+				"EMAIL": column("EMAIL", "classification:pii"),
+			}},
+			"model.shop.customer_stats": {Database: "db", Schema: "marts", Name: "customer_stats", ResourceType: "model", DependsOn: manifest.NodeDependsOn{Nodes: []string{"model.shop.customers"}}, Columns: map[string]manifest.Column{
+				"customers": column("customers"),
+			}},
+			"model.shop.email_report": {Database: "db", Schema: "marts", Name: "email_report", ResourceType: "model", DependsOn: manifest.NodeDependsOn{Nodes: []string{"model.shop.customer_stats"}}, Columns: map[string]manifest.Column{
+				"email": column("email"),
+			}},
+		},
+	}
+
+	propagated := func(fullname string, value string) tag.TagImportObject {
+		return tag.TagImportObject{DataObjectFullName: utils.Ptr(fullname), Key: "classification", StringValue: value, Source: "dbt-shop-propagated"}
+	}
+
+	tagSyncer := NewTagImportService(manifest.NewManifestParser(), hclog.NewNullLogger(), DefinedTagSeparator{separatorKey: ":"})
+	tagHandler := mocks.NewSimpleTagHandler(t, 1)
+
+	source, err := tagSyncer.propagateTagsFromManifest(manifestData, naming.NewNamer("", naming.CasingAdapter), nil, tagHandler, parsePropagateTagKeys("classification"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "dbt-shop-propagated", source)
+	assert.ElementsMatch(t, []tag.TagImportObject{
+		propagated("db.staging.stg_customers", "confidential"),
+		propagated("db.marts.customer_stats", "confidential"),
+		propagated("db.marts.email_report", "confidential"),
+		propagated("db.staging.stg_customers.email", "pii"),
+	}, tagHandler.Tags)
+}
+
+func Test_propagateTagsFromManifest_Sources(t *testing.T) {
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Sources: map[string]manifest.Node{
+			"source.shop.crm.customers": {Database: "raw", Schema: "crm", Name: "customers", ResourceType: "source", Tags: []string{"classification:confidential"}, Columns: map[string]manifest.Column{
+				"email": {Name: "email", Tags: []string{"classification:pii"}},
+			}},
+		},
+		Nodes: map[string]manifest.Node{
+			"model.shop.stg_customers": {Database: "db", Schema: "staging", Name: "stg_customers", ResourceType: "model", DependsOn: manifest.NodeDependsOn{Nodes: []string{"source.shop.crm.customers"}}, Columns: map[string]manifest.Column{
+				"email": {Name: "email"},
+			}},
+			"model.shop.customers": {Database: "db", Schema: "marts", Name: "customers", ResourceType: "model", DependsOn: manifest.NodeDependsOn{Nodes: []string{"model.shop.stg_customers"}}, Columns: map[string]manifest.Column{
+				"email": {Name: "email"},
+			}},
+		},
+	}
+
+	propagated := func(fullname string, value string) tag.TagImportObject {
+		return tag.TagImportObject{DataObjectFullName: utils.Ptr(fullname), Key: "classification", StringValue: value, Source: "dbt-shop-propagated"}
+	}
+
+	tagSyncer := NewTagImportService(manifest.NewManifestParser(), hclog.NewNullLogger(), DefinedTagSeparator{separatorKey: ":"})
+	tagHandler := mocks.NewSimpleTagHandler(t, 1)
+
+	_, err := tagSyncer.propagateTagsFromManifest(manifestData, naming.NewNamer("", naming.CasingAdapter), nil, tagHandler, parsePropagateTagKeys("classification"))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []tag.TagImportObject{
+		propagated("db.staging.stg_customers", "confidential"),
+		propagated("db.marts.customers", "confidential"),
+		propagated("db.staging.stg_customers.email", "pii"),
+		propagated("db.marts.customers.email", "pii"),
+	}, tagHandler.Tags)
+}
+
+func Test_propagateTagsFromManifest_NoKeys(t *testing.T) {
+	manifestData := &manifest.Manifest{
+		Metadata: manifest.Metadata{ProjectName: "shop"},
+		Nodes: map[string]manifest.Node{
+			"seed.shop.raw_customers": {Database: "db", Schema: "raw", Name: "raw_customers", ResourceType: "seed", Tags: []string{"classification:confidential"}},
+			"model.shop.customers":    {Database: "db", Schema: "marts", Name: "customers", ResourceType: "model", DependsOn: manifest.NodeDependsOn{Nodes: []string{"seed.shop.raw_customers"}}},
+		},
+	}
+
+	tagSyncer := NewTagImportService(manifest.NewManifestParser(), hclog.NewNullLogger(), DefinedTagSeparator{separatorKey: ":"})
+	tagHandler := mocks.NewSimpleTagHandler(t, 1)
+
+	// The source is still returned, so tags propagated by a previous sync are removed
+	source, err := tagSyncer.propagateTagsFromManifest(manifestData, naming.NewNamer("", naming.CasingAdapter), nil, tagHandler, parsePropagateTagKeys(""))
+	require.NoError(t, err)
+
+	assert.Equal(t, "dbt-shop-propagated", source)
+	assert.Empty(t, tagHandler.Tags)
+}
+
+func Test_parsePropagateTagKeys(t *testing.T) {
+	assert.ElementsMatch(t, []string{"tag", "classification"}, parsePropagateTagKeys(" tag, classification,,").Slice())
+	assert.Empty(t, parsePropagateTagKeys(""))
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/raito-io/cli-plugin-dbt/internal/constants"
 	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
 	"github.com/raito-io/cli-plugin-dbt/internal/utils"
 )
 
@@ -21,7 +22,7 @@ const TagKey = "tag"
 
 //go:generate go run github.com/vektra/mockery/v2 --name=Parser --with-expecter
 type Parser interface {
-	LoadManifest(path string) (*manifest.Manifest, error)
+	LoadManifest(ctx context.Context, location string, ops ...func(options *manifest.LoadOptions)) (*manifest.Manifest, error)
 }
 
 type TagSeparator interface {
@@ -42,55 +43,98 @@ func NewTagImportService(manifestParser Parser, logger hclog.Logger, separator T
 	}
 }
 
-func (t *TagImportService) SyncTags(_ context.Context, tagsHandler wrappers.TagHandler, config *tag.TagSyncConfig) ([]string, error) {
-	manifestFile := config.ConfigMap.GetString(constants.ManifestParameterName)
+func (t *TagImportService) SyncTags(ctx context.Context, tagsHandler wrappers.TagHandler, config *tag.TagSyncConfig) ([]string, error) {
+	manifestFile, err := utils.GetManifestLocation(config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
 
-	manifestData, err := t.manifestParser.LoadManifest(manifestFile)
+	loadOptions, err := utils.GetManifestLoadOptions(config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	mesh, err := manifest.LoadMesh(ctx, t.manifestParser, manifestFile, loadOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("load file %s: %w", manifestFile, err)
 	}
 
-	prefix := utils.GetFullnamePrefix(config.ConfigMap)
+	selection, err := utils.GetSelection(config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
 
-	return t.loadTagsFromManifest(manifestData, prefix, tagsHandler)
-}
+	namer, err := utils.GetNamer(config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
 
-func (t *TagImportService) loadTagsFromManifest(manifestData *manifest.Manifest, fullnamePrefix string, tagsHandler wrappers.TagHandler) ([]string, error) {
-	supportedResourceTypes := set.NewSet("model", "seed", "snapshot")
+	propagateKeys := parsePropagateTagKeys(config.ConfigMap.GetString(constants.PropagateTagKeysParameterName))
 
-	source := fmt.Sprintf("dbt-%s", manifestData.Metadata.ProjectName)
+	sources := make([]string, 0, len(mesh.Manifests))
 
-	for i := range manifestData.Nodes {
-		if !supportedResourceTypes.Contains(manifestData.Nodes[i].ResourceType) {
-			continue
+	for _, manifestData := range mesh.Manifests {
+		source, loadErr := t.loadTagsFromManifest(manifestData, namer, selection, tagsHandler)
+		if loadErr != nil {
+			return nil, loadErr
 		}
 
-		databaseName := manifestData.Nodes[i].Database
-		schemaName := manifestData.Nodes[i].Schema
-		modelName := manifestData.Nodes[i].Name
-		doName := fmt.Sprintf("%s%s.%s.%s", fullnamePrefix, databaseName, schemaName, modelName)
+		// The propagated source is always returned, so previously propagated tags are removed when propagation is turned off
+		propagatedSource, propagateErr := t.propagateTagsFromManifest(manifestData, namer, selection, tagsHandler, propagateKeys)
+		if propagateErr != nil {
+			return nil, propagateErr
+		}
 
-		doTags := set.NewSet[string](manifestData.Nodes[i].Tags...)
-		doTags.Add(manifestData.Nodes[i].Config.Tags...)
+		sources = append(sources, source, propagatedSource)
+	}
 
-		err := t.addTags(tagsHandler, doName, source, doTags)
+	return sources, nil
+}
+
+func (t *TagImportService) loadTagsFromManifest(manifestData *manifest.Manifest, namer *naming.Namer, selection *manifest.Selection, tagsHandler wrappers.TagHandler) (string, error) {
+	source := fmt.Sprintf("dbt-%s", manifestData.Metadata.ProjectName)
+
+	nodes, err := manifestData.SelectDataObjectNodes(selection)
+	if err != nil {
+		return "", fmt.Errorf("select nodes: %w", err)
+	}
+
+	for _, node := range nodes {
+		doName, nameErr := namer.DataObject(manifestData, node)
+		if nameErr != nil {
+			return "", fmt.Errorf("data object name of %s: %w", node.UniqueId, nameErr)
+		}
+
+		doTags := set.NewSet[string](node.Tags...)
+		doTags.Add(node.Config.Tags...)
+
+		err = t.addTags(tagsHandler, doName, source, doTags)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
-		for columnName := range manifestData.Nodes[i].Columns {
-			columnFullName := fmt.Sprintf("%s.%s", doName, columnName)
-			columnTags := set.NewSet[string](manifestData.Nodes[i].Columns[columnName].Tags...)
-			columnTags.Add(manifestData.Nodes[i].Columns[columnName].Config.Tags...)
+		for columnName := range node.Columns {
+			column := node.Columns[columnName]
+			if column.Name == "" {
+				column.Name = columnName
+			}
+
+			columnFullName, nameErr := namer.Column(manifestData, node, &column)
+			if nameErr != nil {
+				return "", fmt.Errorf("column name of %s.%s: %w", node.UniqueId, columnName, nameErr)
+			}
+
+			columnTags := set.NewSet[string](column.Tags...)
+			columnTags.Add(column.Config.Tags...)
 
 			err = t.addTags(tagsHandler, columnFullName, source, columnTags)
 			if err != nil {
-				return nil, err
+				return "", err
 			}
 		}
 	}
 
-	return []string{source}, nil
+	return source, nil
 }
 
 func (t *TagImportService) addTags(tagsHandler wrappers.TagHandler, doFullName string, source string, tags set.Set[string]) error {
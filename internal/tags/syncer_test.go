@@ -13,6 +13,7 @@ import (
 
 	"github.com/raito-io/cli-plugin-dbt/internal/constants"
 	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
 )
 
 func TestTagImportService_SyncTags(t *testing.T) {
@@ -40,7 +41,7 @@ func TestTagImportService_SyncTags(t *testing.T) {
 					DataSourceId: "datSourceId1",
 				},
 			},
-			wantSources: []string{"dbt-dbt_bq_demo"},
+			wantSources: []string{"dbt-dbt_bq_demo", "dbt-dbt_bq_demo-propagated"},
 			wantTags: []tag.TagImportObject{
 				{
 					DataObjectFullName: utils.Ptr("bq-demodata.dbt_company.new_customers"),
@@ -74,6 +75,106 @@ func TestTagImportService_SyncTags(t *testing.T) {
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "manifest1 exclude seeds",
+			args: args{
+				config: &tag.TagSyncConfig{
+					ConfigMap: &config.ConfigMap{
+						Parameters: map[string]string{
+							constants.ManifestParameterName: "testdata/manifest_1.json",
+							constants.SelectParameterName:   "tag:raito_tag_*",
+							constants.ExcludeParameterName:  "path:seeds",
+						},
+					},
+					DataSourceId: "datSourceId1",
+				},
+			},
+			wantSources: []string{"dbt-dbt_bq_demo", "dbt-dbt_bq_demo-propagated"},
+			wantTags: []tag.TagImportObject{
+				{
+					DataObjectFullName: utils.Ptr("bq-demodata.dbt_company.new_customers"),
+					Key:                "tag",
+					StringValue:        "raito_tag_1",
+					Source:             "dbt-dbt_bq_demo",
+				},
+				{
+					DataObjectFullName: utils.Ptr("bq-demodata.dbt_company.new_customers"),
+					Key:                "tag",
+					StringValue:        "raito_tag_2",
+					Source:             "dbt-dbt_bq_demo",
+				},
+				{
+					DataObjectFullName: utils.Ptr("bq-demodata.dbt_company.new_customers.Email"),
+					Key:                "tag",
+					StringValue:        "raito_tag_2",
+					Source:             "dbt-dbt_bq_demo",
+				}, {
+					DataObjectFullName: utils.Ptr("bq-demodata.dbt_company.new_customers.Email"),
+					Key:                "tag",
+					StringValue:        "raito_tag_3",
+					Source:             "dbt-dbt_bq_demo",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "manifest1 fullname template",
+			args: args{
+				config: &tag.TagSyncConfig{
+					ConfigMap: &config.ConfigMap{
+						Parameters: map[string]string{
+							constants.ManifestParameterName:         "testdata/manifest_1.json",
+							constants.SelectParameterName:           "new_customers",
+							constants.FullnameTemplateParameterName: "{{.Database}}.{{.Schema | trimPrefix \"dbt_\"}}.{{.Alias}}",
+							constants.DatabaseMappingParameterName:  "bq-demodata=bq-production",
+						},
+					},
+					DataSourceId: "datSourceId1",
+				},
+			},
+			wantSources: []string{"dbt-dbt_bq_demo", "dbt-dbt_bq_demo-propagated"},
+			wantTags: []tag.TagImportObject{
+				{
+					DataObjectFullName: utils.Ptr("bq-production.company.new_customers"),
+					Key:                "tag",
+					StringValue:        "raito_tag_1",
+					Source:             "dbt-dbt_bq_demo",
+				},
+				{
+					DataObjectFullName: utils.Ptr("bq-production.company.new_customers"),
+					Key:                "tag",
+					StringValue:        "raito_tag_2",
+					Source:             "dbt-dbt_bq_demo",
+				},
+				{
+					DataObjectFullName: utils.Ptr("bq-production.company.new_customers.Email"),
+					Key:                "tag",
+					StringValue:        "raito_tag_2",
+					Source:             "dbt-dbt_bq_demo",
+				}, {
+					DataObjectFullName: utils.Ptr("bq-production.company.new_customers.Email"),
+					Key:                "tag",
+					StringValue:        "raito_tag_3",
+					Source:             "dbt-dbt_bq_demo",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "invalid selector",
+			args: args{
+				config: &tag.TagSyncConfig{
+					ConfigMap: &config.ConfigMap{
+						Parameters: map[string]string{
+							constants.ManifestParameterName: "testdata/manifest_1.json",
+							constants.SelectParameterName:   "unknown:value",
+						},
+					},
+					DataSourceId: "datSourceId1",
+				},
+			},
+			wantErr: assert.Error,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -100,11 +201,11 @@ func Test_loadTagsFromManifest(t *testing.T) {
 		manifestData *manifest.Manifest
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantSources []string
-		wantTags    []tag.TagImportObject
-		wantErr     bool
+		name       string
+		args       args
+		wantSource string
+		wantTags   []tag.TagImportObject
+		wantErr    bool
 	}{
 		{
 			name: "No tags",
@@ -143,9 +244,9 @@ func Test_loadTagsFromManifest(t *testing.T) {
 					},
 				},
 			},
-			wantSources: []string{"dbt-project-name-1"},
-			wantTags:    []tag.TagImportObject{},
-			wantErr:     false,
+			wantSource: "dbt-project-name-1",
+			wantTags:   []tag.TagImportObject{},
+			wantErr:    false,
 		},
 		{
 			name: "Found tags",
@@ -194,7 +295,7 @@ func Test_loadTagsFromManifest(t *testing.T) {
 					},
 				},
 			},
-			wantSources: []string{"dbt-project-name-2"},
+			wantSource: "dbt-project-name-2",
 			wantTags: []tag.TagImportObject{
 				{
 					DataObjectFullName: utils.Ptr("prefix.db.schema.model1"),
@@ -251,13 +352,13 @@ func Test_loadTagsFromManifest(t *testing.T) {
 
 			tagHandler := mocks.NewSimpleTagHandler(t, 1)
 
-			got, err := tagSyncer.loadTagsFromManifest(tt.args.manifestData, "prefix.", tagHandler)
+			got, err := tagSyncer.loadTagsFromManifest(tt.args.manifestData, naming.NewNamer("prefix.", naming.CasingAdapter), nil, tagHandler)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("loadTagsFromManifest() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
-			assert.ElementsMatchf(t, got, tt.wantSources, "loadTagsFromManifest() return sources %v, want %v", got, tt.wantSources)
+			assert.Equalf(t, tt.wantSource, got, "loadTagsFromManifest() return source %v, want %v", got, tt.wantSource)
 
 			assert.ElementsMatchf(t, tagHandler.Tags, tt.wantTags, "loadTagsFromManifest() created tags %v, want %v", tagHandler.Tags, tt)
 		})
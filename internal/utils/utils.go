@@ -1,11 +1,17 @@
 package utils
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/hashicorp/go-hclog"
 	"github.com/raito-io/cli/base"
 	"github.com/raito-io/cli/base/util/config"
 
 	"github.com/raito-io/cli-plugin-dbt/internal/constants"
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+	"github.com/raito-io/cli-plugin-dbt/internal/naming"
 )
 
 var logger hclog.Logger
@@ -27,3 +33,136 @@ func GetFullnamePrefix(cfg *config.ConfigMap) string {
 		return prefix + "."
 	}
 }
+
+// GetNamer returns the namer used to build the data object fullnames, based on the do-prefix, identifier-casing, fullname-template
+// and database and schema mapping parameters.
+func GetNamer(cfg *config.ConfigMap) (*naming.Namer, error) {
+	casing, err := naming.ParseCasing(cfg.GetString(constants.IdentifierCasingParameterName))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", constants.IdentifierCasingParameterName, err)
+	}
+
+	var options []func(namer *naming.Namer)
+
+	if fullnameTemplate := cfg.GetString(constants.FullnameTemplateParameterName); fullnameTemplate != "" {
+		tmpl, tmplErr := naming.ParseTemplate(fullnameTemplate)
+		if tmplErr != nil {
+			return nil, fmt.Errorf("parse %s: %w", constants.FullnameTemplateParameterName, tmplErr)
+		}
+
+		options = append(options, naming.WithTemplate(tmpl))
+	}
+
+	databaseMapping, err := naming.ParseMapping(cfg.GetString(constants.DatabaseMappingParameterName))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", constants.DatabaseMappingParameterName, err)
+	}
+
+	schemaMapping, err := naming.ParseMapping(cfg.GetString(constants.SchemaMappingParameterName))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", constants.SchemaMappingParameterName, err)
+	}
+
+	options = append(options, naming.WithDatabaseMapping(databaseMapping), naming.WithSchemaMapping(schemaMapping))
+
+	return naming.NewNamer(GetFullnamePrefix(cfg), casing, options...), nil
+}
+
+// GetManifestLocation returns the configured manifest location.
+// If no manifest is configured, the manifest of a dbt Cloud run (or the latest successful run of a dbt Cloud job) is used.
+func GetManifestLocation(cfg *config.ConfigMap) (string, error) {
+	if location := cfg.GetString(constants.ManifestParameterName); location != "" {
+		return location, nil
+	}
+
+	accountId := cfg.GetString(constants.DbtCloudAccountIdParameterName)
+	if accountId == "" {
+		return "", fmt.Errorf("either %s or %s should be defined", constants.ManifestParameterName, constants.DbtCloudAccountIdParameterName)
+	}
+
+	if runId := cfg.GetString(constants.DbtCloudRunIdParameterName); runId != "" {
+		return manifest.DbtCloudRunLocation(accountId, runId), nil
+	}
+
+	if jobId := cfg.GetString(constants.DbtCloudJobIdParameterName); jobId != "" {
+		return manifest.DbtCloudLocation(accountId, jobId), nil
+	}
+
+	return "", fmt.Errorf("either %s or %s should be defined to load the manifest from dbt Cloud", constants.DbtCloudJobIdParameterName, constants.DbtCloudRunIdParameterName)
+}
+
+func GetManifestLoadOptions(cfg *config.ConfigMap) ([]func(options *manifest.LoadOptions), error) {
+	var options []func(options *manifest.LoadOptions)
+
+	if token := cfg.GetString(constants.ManifestTokenParameterName); token != "" {
+		options = append(options, manifest.WithBearerToken(token))
+	}
+
+	if token := cfg.GetString(constants.DbtCloudTokenParameterName); token != "" {
+		options = append(options, manifest.WithDbtCloudToken(token))
+	}
+
+	if baseUrl := cfg.GetString(constants.DbtCloudUrlParameterName); baseUrl != "" {
+		options = append(options, manifest.WithDbtCloudBaseUrl(baseUrl))
+	}
+
+	if checksum := cfg.GetString(constants.ManifestChecksumParameterName); checksum != "" {
+		options = append(options, manifest.WithChecksum(checksum))
+	}
+
+	if maxSize := cfg.GetString(constants.ManifestMaxSizeParameterName); maxSize != "" {
+		megabytes, err := strconv.ParseInt(maxSize, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: invalid size %q: %w", constants.ManifestMaxSizeParameterName, maxSize, err)
+		}
+
+		options = append(options, manifest.WithMaxSize(megabytes<<20))
+	}
+
+	if timeout := cfg.GetString(constants.ManifestTimeoutParameterName); timeout != "" {
+		duration, err := parseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", constants.ManifestTimeoutParameterName, err)
+		}
+
+		options = append(options, manifest.WithTimeout(duration))
+	}
+
+	return options, nil
+}
+
+// GetSelection returns the node selection defined by the select and exclude parameters, using the dbt node selection syntax,
+// or the named selector defined by the selector parameter.
+func GetSelection(cfg *config.ConfigMap) (*manifest.Selection, error) {
+	selectExpression := cfg.GetString(constants.SelectParameterName)
+	excludeExpression := cfg.GetString(constants.ExcludeParameterName)
+
+	if selectorName := cfg.GetString(constants.SelectorParameterName); selectorName != "" {
+		if selectExpression != "" || excludeExpression != "" {
+			return nil, fmt.Errorf("%s can not be combined with %s or %s", constants.SelectorParameterName, constants.SelectParameterName, constants.ExcludeParameterName)
+		}
+
+		return manifest.NamedSelection(selectorName), nil
+	}
+
+	selection, err := manifest.ParseSelection(selectExpression, excludeExpression)
+	if err != nil {
+		return nil, fmt.Errorf("node selection: %w", err)
+	}
+
+	return selection, nil
+}
+
+// parseDuration parses a go duration string. A plain number is interpreted as a number of seconds.
+func parseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+
+	return duration, nil
+}
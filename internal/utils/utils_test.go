@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli-plugin-dbt/internal/constants"
+	"github.com/raito-io/cli-plugin-dbt/internal/manifest"
+)
+
+func TestGetManifestLoadOptions_Tokens(t *testing.T) {
+	var mutex sync.Mutex
+
+	authorization := make(map[string]string)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		authorization[r.URL.Path] = r.Header.Get("Authorization")
+		mutex.Unlock()
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	options, err := GetManifestLoadOptions(&config.ConfigMap{Parameters: map[string]string{
+		constants.ManifestTokenParameterName: "manifest-token",
+		constants.DbtCloudTokenParameterName: "dbt-cloud-token",
+		constants.DbtCloudUrlParameterName:   server.URL,
+	}})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = manifest.NewManifestParser().LoadManifest(ctx, server.URL+"/manifest.json", options...)
+	require.Error(t, err)
+
+	_, err = manifest.NewManifestParser().LoadManifest(ctx, manifest.DbtCloudRunLocation("42", "1001"), options...)
+	require.Error(t, err)
+
+	assert.Equal(t, map[string]string{
+		"/manifest.json": "Bearer manifest-token",
+		"/api/v2/accounts/42/runs/1001/artifacts/manifest.json": "Bearer dbt-cloud-token",
+	}, authorization)
+}
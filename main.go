@@ -26,9 +26,36 @@ func main() {
 				Name:    "dbt",
 				Version: plugin.ParseVersion(version),
 				Parameters: []*plugin.ParameterInfo{
-					{Name: constants.ManifestParameterName, Description: "The manifest.json file generated by dbt", Mandatory: true},
+					{Name: constants.ManifestParameterName, Description: "The manifest.json file generated by dbt. This can be a local file path, a file:// or http(s):// url, or '-' to read the manifest from stdin. Gzip (.json.gz) and zstd (.zst) compressed manifests are supported. Multiple manifests of a dbt Mesh can be provided as a comma separated list, local paths can contain glob patterns. Mandatory if the manifest is not loaded from dbt Cloud.", Mandatory: false},
+					{Name: constants.ManifestTokenParameterName, Description: "Bearer token used to download the manifest when an http(s) url is provided.", Mandatory: false},
+					{Name: constants.ManifestTimeoutParameterName, Description: "Timeout to download a remote manifest, e.g. `30s` or `2m`. A plain number is interpreted as seconds. Defaults to 60 seconds.", Mandatory: false},
+					{Name: constants.ManifestChecksumParameterName, Description: "Expected sha256 checksum of the manifest file (as stored, before decompression). Loading fails when the checksum does not match. Use a comma separated list with one checksum per manifest when multiple manifests are combined.", Mandatory: false},
+					{Name: constants.ManifestMaxSizeParameterName, Description: "Maximum size in MB of a downloaded manifest, and of a manifest after decompression. Defaults to 1024 MB.", Mandatory: false},
+					{Name: constants.DbtCloudAccountIdParameterName, Description: "The dbt Cloud account id. When defined (and no manifest is provided), the manifest is downloaded from dbt Cloud.", Mandatory: false},
+					{Name: constants.DbtCloudJobIdParameterName, Description: "The dbt Cloud job id. The manifest of the latest successful run of the job is used.", Mandatory: false},
+					{Name: constants.DbtCloudRunIdParameterName, Description: "The dbt Cloud run id. The manifest of this run is used. Takes precedence over the job id.", Mandatory: false},
+					{Name: constants.DbtCloudTokenParameterName, Description: "The dbt Cloud API token (service token or personal access token) used to download the manifest.", Mandatory: false},
+					{Name: constants.DbtCloudUrlParameterName, Description: "The base url of the dbt Cloud API. Defaults to https://cloud.getdbt.com", Mandatory: false},
+					{Name: constants.SelectParameterName, Description: "Only sync the models, seeds and snapshots matching this dbt node selection, e.g. `tag:finance path:models/marts +orders`. Supports the tag, path, package, fqn, config.materialized, group and access methods and the +, n+ and @ graph operators.", Mandatory: false},
+					{Name: constants.ExcludeParameterName, Description: "Exclude the models, seeds and snapshots matching this dbt node selection, e.g. `package:dbt_utils`.", Mandatory: false},
+					{Name: constants.SelectorParameterName, Description: "Only sync the models, seeds and snapshots selected by this named selector, defined in the selectors.yml file of the dbt project. Can not be combined with select or exclude.", Mandatory: false},
 					{Name: constants.FullNamePrefixParameterName, Description: "Data object prefix to match data objects within Raito. Check docs.raito.io for the correct prefix depending on the data source type", Mandatory: false},
+					{Name: constants.FullnameTemplateParameterName, Description: "Go text/template used to build the data object fullnames instead of prepending the do-prefix, e.g. `{{.Database}}.{{.Schema}}.{{.Alias}}`. Available fields: Prefix, Database, Schema, Alias, Name, Column, Adapter and Project. When the template does not use Column, the column name is appended to the data object fullname.", Mandatory: false},
+					{Name: constants.DatabaseMappingParameterName, Description: "Comma separated list of `<dbt database>=<database>` pairs to rename the databases defined in the manifest, e.g. `dev_analytics=analytics`.", Mandatory: false},
+					{Name: constants.SchemaMappingParameterName, Description: "Comma separated list of `<dbt schema>=<schema>` pairs to rename the schemas defined in the manifest.", Mandatory: false},
+					{Name: constants.IdentifierCasingParameterName, Description: "Casing of the database, schema, table and column names of the data objects: `upper`, `lower` or `preserve`. By default the casing is derived from the adapter of the manifest and the quoting configuration (e.g. unquoted identifiers are upper case on Snowflake).", Mandatory: false},
+					{Name: constants.ModelVersionsParameterName, Description: "Defines on which versions of versioned models the policies (grants, filters and masks) are applied: `all` (default) or `latest`.", Mandatory: false},
+					{Name: constants.PolicyRuleVariablesParameterName, Description: "Comma separated list of `<name>=<value>` pairs that can be used in the policy rules of filters as `{{ var.<name> }}`. Enclose a value in double quotes to use commas, e.g. `regions=\"'EU','US'\"`.", Mandatory: false},
+					{Name: constants.DbtTargetParameterName, Description: "Comma separated list of `<key>=<value>` pairs describing the dbt target the manifest is built with, e.g. `name=prod,database=analytics,schema=dbt_prod`. The values can be used in the policy rules of filters as `{{ target.<key> }}`.", Mandatory: false},
+					{Name: constants.TemplatesFileParameterName, Description: "Path to a yaml file defining reusable grant and filter templates, referenced from the raito meta by `template`. A grant template is expanded into one grant shared by all models, a filter template into one filter per model, named `<template>-<model>`.", Mandatory: false},
+					{Name: constants.CrossTableMasksParameterName, Description: "If set to true, a mask can be applied on columns of multiple tables. Only enable this if the data source supports masks across tables. Otherwise, a mask defined on multiple tables is split into one mask per table, named `<mask>-<model>`.", Mandatory: false},
+					{Name: constants.MaskTypeMappingParameterName, Description: "Comma separated list of `<data type>=<mask type>` pairs that define the type of masks without type, based on the `data_type` of the column, e.g. `string=SHA256,date=NULL`. The data type can be a type name (e.g. `varchar`) or one of the categories string, number, date, timestamp and boolean.", Mandatory: false},
+					{Name: constants.CatalogParameterName, Description: "Location of the catalog.json generated by `dbt docs generate`, in the same forms as the manifest (for dbt Cloud, the catalog.json artifact of the run is used). Used for the data types of columns without `data_type` in the mask type mapping. Use a comma separated list for multiple projects.", Mandatory: false},
+					{Name: constants.CatalogTokenParameterName, Description: "Bearer token sent when the catalog is downloaded over http(s). The manifest-token is not sent to catalog locations. dbt Cloud catalogs use the dbt-cloud-token.", Mandatory: false},
+					{Name: constants.RulesFileParameterName, Description: "Path to a yaml file defining rules that add grants and filters to all models, and masks to all columns, whose tags or meta match a predicate, e.g. a mask on every column tagged `pii`. Policies defined in the raito meta take precedence.", Mandatory: false},
+					{Name: constants.PoliciesFileParameterName, Description: "Path to a yaml file declaring grants, filters and masks outside the dbt meta. Each policy targets nodes with a dbt selection (`select` and `exclude`) and/or a list of `unique_ids`. Policies defined in the raito meta take precedence.", Mandatory: false},
 					{Name: constants.TagSplitKey, Description: "Characters to split the tag name and value in the dbt manifest file. When no split key is defined the key will be `tag` and the value the string defined in DBT.", Mandatory: false},
+					{Name: constants.PropagateTagKeysParameterName, Description: "Comma separated list of tag keys (e.g. `tag` or `classification`) whose tags are propagated to all downstream models. Column tags are propagated to downstream columns with the same name. Propagated tags are imported with the source `dbt-<project>-propagated`.", Mandatory: false},
 				},
 				Type: []plugin.PluginType{
 					plugin.PluginType_PLUGIN_TYPE_RESOURCE_PROVIDER,